@@ -0,0 +1,155 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/clients/krystal"
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/trader"
+)
+
+const (
+	routingKrystal = "krystal"
+
+	routerTypeSwapRouter02 = "swap_router02"
+	routerTypeUniversal    = "universal"
+
+	dexUniswapV3 = "uniswap_v3"
+	dexUniswapV2 = "uniswap_v2"
+	dexAerodrome = "aerodrome"
+)
+
+//nolint:gochecknoglobals
+var ethAddress = common.HexToAddress("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+
+// NewDex creates the calldata builder of the configured dex and its quoter,
+// which is nil when quoting is not possible.
+func NewDex(cfg config.Config, client trader.ContractCaller) (trader.CalldataBuilder, trader.Quoter, error) {
+	router, weth := common.HexToAddress(cfg.RouterAddress), common.HexToAddress(cfg.Weth)
+
+	var builder trader.CalldataBuilder
+	var quoter trader.Quoter
+	switch cfg.Dex {
+	case "", dexUniswapV3:
+		route := trader.DirectRoute(big.NewInt(int64(cfg.FeeTier)))
+		if len(cfg.Route) > 0 {
+			// Pool triggers watch the input_token/output_token pool of
+			// fee_tier, which a route may not swap through.
+			if hasPoolTrigger(cfg) {
+				return nil, nil, errors.New("route can not be used with start_on_pool_created or start_on_liquidity_added")
+			}
+
+			var err error
+			if route, err = trader.ParseRoute(cfg.Route); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		switch cfg.RouterType {
+		case "", routerTypeSwapRouter02:
+			if cfg.Permit2Address != "" {
+				return nil, nil, errors.New("permit2 requires the universal router")
+			}
+			builder = trader.NewUniswapV3Builder(router, weth, route)
+		case routerTypeUniversal:
+			// The Universal Router only pulls ERC20 inputs through Permit2.
+			if cfg.Permit2Address == "" && !isEthInput(cfg) {
+				return nil, nil, errors.New("universal router requires permit2_address for an ERC20 input")
+			}
+			builder = trader.NewUniversalRouterBuilder(router, weth, route)
+		default:
+			return nil, nil, fmt.Errorf("invalid router type: %s", cfg.RouterType)
+		}
+
+		if cfg.QuoterAddress != "" {
+			quoter = trader.NewUniswapV3Quoter(client, common.HexToAddress(cfg.QuoterAddress), weth, route)
+		}
+	case dexUniswapV2:
+		// Pool triggers watch Uniswap V3 pools only.
+		if hasPoolTrigger(cfg) {
+			return nil, nil, errors.New("uniswap_v2 can not be used with start_on_pool_created or start_on_liquidity_added")
+		}
+
+		path, err := trader.ParseV2Path(cfg.Path)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		builder = trader.NewUniswapV2Builder(router, weth, path, cfg.FeeOnTransfer)
+		quoter = trader.NewUniswapV2Quoter(client, router, weth, path)
+	case dexAerodrome:
+		// Aerodrome pools are created per stable or volatile route by their
+		// own factory, which the pool triggers do not watch.
+		if hasPoolTrigger(cfg) {
+			return nil, nil, errors.New("aerodrome can not be used with start_on_pool_created or start_on_liquidity_added")
+		}
+
+		routes, err := trader.ParseAerodromeRoutes(cfg.AerodromeRoutes)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		builder = trader.NewAerodromeBuilder(router, weth, routes, cfg.FeeOnTransfer)
+		quoter = trader.NewAerodromeQuoter(client, router, weth, routes)
+	default:
+		return nil, nil, fmt.Errorf("invalid dex: %s", cfg.Dex)
+	}
+
+	if cfg.Routing == routingKrystal {
+		// The Krystal router is only known once the swap is built, too late
+		// to approve an ERC20 input.
+		if !isEthInput(cfg) {
+			return nil, nil, errors.New("krystal routing requires an ETH input")
+		}
+
+		krystalClient, err := krystal.NewClient(cfg.KrystalAPI, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("create krystal client: %w", err)
+		}
+		builder = trader.NewKrystalBuilder(krystalClient, cfg.PlatformWallet, builder)
+	}
+
+	return builder, quoter, nil
+}
+
+// FindFeeTier finds the fee tier of fee_tier auto, comparing the quotes of the
+// first account amount when quoter_address is set. The pool must already
+// exist, so it can not be combined with a pool trigger.
+func FindFeeTier(ctx context.Context, cfg config.Config, client trader.ContractCaller) (int64, error) {
+	if hasPoolTrigger(cfg) {
+		return 0, errors.New("fee_tier auto can not be used with start_on_pool_created or start_on_liquidity_added")
+	}
+	if cfg.FactoryAddress == "" {
+		return 0, errors.New("factory_address is required by fee_tier auto")
+	}
+
+	params := trader.SwapParams{
+		InputToken:  common.HexToAddress(cfg.InputToken),
+		OutputToken: common.HexToAddress(cfg.OutputToken),
+	}
+	if len(cfg.Accounts) > 0 && !cfg.Accounts[0].ExactOutput {
+		params.AmountIn = cfg.Accounts[0].InputAmount
+	}
+
+	var quoter common.Address
+	if cfg.QuoterAddress != "" {
+		quoter = common.HexToAddress(cfg.QuoterAddress)
+	}
+
+	finder := trader.NewFeeTierFinder(
+		client, common.HexToAddress(cfg.FactoryAddress), common.HexToAddress(cfg.Weth), quoter)
+	return finder.Find(ctx, params, cfg.FeeTiers)
+}
+
+func hasPoolTrigger(cfg config.Config) bool {
+	return cfg.StartOnPoolCreated || cfg.StartOnLiquidityAdded
+}
+
+func isEthInput(cfg config.Config) bool {
+	return common.HexToAddress(cfg.InputToken) == ethAddress
+}
@@ -0,0 +1,122 @@
+package app
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/trader"
+)
+
+type fakeContractCaller struct{}
+
+func (fakeContractCaller) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	return make([]byte, 32), nil
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		ChainID:       1,
+		RouterAddress: "0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45",
+		InputToken:    "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
+		OutputToken:   "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		FeeTier:       500,
+		Weth:          "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+	}
+}
+
+func TestNewDex(t *testing.T) {
+	cfg := testConfig()
+	builder, quoter, err := NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+	require.IsType(t, &trader.UniswapV3Builder{}, builder)
+	require.Nil(t, quoter)
+
+	cfg.QuoterAddress = "0x61ffe014ba17989e743c5f6cb21bf9697530b21e"
+	_, quoter, err = NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+	require.IsType(t, &trader.UniswapV3Quoter{}, quoter)
+
+	cfg.Permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "permit2 requires the universal router")
+
+	cfg.RouterType = routerTypeUniversal
+	builder, _, err = NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+	require.IsType(t, &trader.UniversalRouterBuilder{}, builder)
+
+	// ERC20 inputs are only pulled through Permit2.
+	cfg.InputToken, cfg.OutputToken = cfg.OutputToken, cfg.InputToken
+	cfg.Permit2Address = ""
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "permit2_address")
+
+	cfg = testConfig()
+	cfg.Route = []string{cfg.Weth, "500", "0xdac17f958d2ee523a2206206994597c13d831ec7", "3000", cfg.OutputToken}
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+
+	cfg.StartOnPoolCreated = true
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "route can not be used")
+
+	cfg = testConfig()
+	cfg.Dex = dexUniswapV2
+	builder, quoter, err = NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+	require.IsType(t, &trader.UniswapV2Builder{}, builder)
+	require.IsType(t, &trader.UniswapV2Quoter{}, quoter)
+
+	// Pool triggers only watch Uniswap V3 pools.
+	cfg.StartOnLiquidityAdded = true
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "start_on_liquidity_added")
+	cfg.StartOnLiquidityAdded = false
+
+	cfg.Dex = dexAerodrome
+	builder, quoter, err = NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+	require.IsType(t, &trader.AerodromeBuilder{}, builder)
+	require.IsType(t, &trader.AerodromeQuoter{}, quoter)
+
+	cfg.StartOnPoolCreated = true
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "start_on_pool_created")
+	cfg.StartOnPoolCreated = false
+
+	cfg.Dex = "curve"
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "invalid dex")
+
+	cfg = testConfig()
+	cfg.Routing = routingKrystal
+	cfg.KrystalAPI = "https://api.krystal.app/ethereum/v2"
+	builder, _, err = NewDex(cfg, fakeContractCaller{})
+	require.NoError(t, err)
+	require.IsType(t, &trader.KrystalBuilder{}, builder)
+
+	cfg.InputToken, cfg.OutputToken = cfg.OutputToken, cfg.InputToken
+	_, _, err = NewDex(cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "krystal routing requires an ETH input")
+}
+
+func TestFindFeeTier(t *testing.T) {
+	cfg := testConfig()
+	_, err := FindFeeTier(context.Background(), cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "factory_address")
+
+	cfg.FactoryAddress = "0x1F98431c8aD98523631AE4a59f267346ea31F984"
+	cfg.StartOnPoolCreated = true
+	_, err = FindFeeTier(context.Background(), cfg, fakeContractCaller{})
+	require.ErrorContains(t, err, "start_on_pool_created")
+
+	// No pool exists for any fee tier.
+	cfg.StartOnPoolCreated = false
+	_, err = FindFeeTier(context.Background(), cfg, fakeContractCaller{})
+	require.Error(t, err)
+}
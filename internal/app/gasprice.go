@@ -0,0 +1,103 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/KyberNetwork/tradinglib/pkg/convert"
+
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/gasprice"
+)
+
+const (
+	gasPricerMetamask   = "metamask"
+	gasPricerFeeHistory = "fee_history"
+	gasPricerStatic     = "static"
+
+	// GasPricerNode asks the node for eth_gasPrice, the default gas pricer of
+	// legacy transactions.
+	GasPricerNode = "eth_gas_price"
+
+	defaultGasPricerCooldown = 30 * time.Second
+	gasPricerCacheTTL        = time.Second
+	gweiDecimals             = 9
+)
+
+// GasPriceClient is the node gas pricers read.
+type GasPriceClient interface {
+	gasprice.FeeHistoryClient
+	gasprice.GasPriceClient
+}
+
+// NewGasPricer creates the gas pricer of the Metamask gas tier, falling back
+// through gas_pricers in order when set.
+func NewGasPricer(cfg config.Config, client GasPriceClient, tier string) (gasprice.GasPricer, error) {
+	if len(cfg.GasPricers) == 0 {
+		return newNamedGasPricer(cfg, client, cfg.GasPricer, tier)
+	}
+
+	backends := make([]gasprice.GasPricer, 0, len(cfg.GasPricers))
+	for _, name := range cfg.GasPricers {
+		backend, err := newNamedGasPricer(cfg, client, name, tier)
+		if err != nil {
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+
+	cooldown := defaultGasPricerCooldown
+	if cfg.GasPricerCooldownSeconds > 0 {
+		cooldown = time.Duration(cfg.GasPricerCooldownSeconds) * time.Second
+	}
+
+	return gasprice.NewFallbackGasPricer(cfg.GasPricers, backends, cooldown), nil
+}
+
+// NewTierGasPricers creates a cached gas pricer for each gas tier set by an
+// account.
+func NewTierGasPricers(cfg config.Config, client GasPriceClient) (map[string]gasprice.GasPricer, error) {
+	tierGasPricers := make(map[string]gasprice.GasPricer)
+	for _, acc := range cfg.Accounts {
+		if acc.GasTier == "" || tierGasPricers[acc.GasTier] != nil {
+			continue
+		}
+
+		gasPricer, err := NewGasPricer(cfg, client, acc.GasTier)
+		if err != nil {
+			return nil, err
+		}
+		tierGasPricers[acc.GasTier] = gasprice.NewCacheGasPricer(gasPricer, gasPricerCacheTTL)
+	}
+
+	return tierGasPricers, nil
+}
+
+func newNamedGasPricer(
+	cfg config.Config, client GasPriceClient, name, tier string,
+) (gasprice.GasPricer, error) {
+	switch name {
+	case "", gasPricerMetamask:
+		gasTier, err := gasprice.ParseTier(tier)
+		if err != nil {
+			return nil, err
+		}
+
+		opts := []gasprice.MetamaskOption{gasprice.WithTier(gasTier)}
+		if cfg.AdaptiveGasTier {
+			opts = append(opts, gasprice.WithAdaptiveTier())
+		}
+		return gasprice.NewMetamaskGasPricer(cfg.GasPriceEndpoint, nil, opts...)
+	case gasPricerFeeHistory:
+		return gasprice.NewFeeHistoryGasPricer(client, cfg.GasPricePercentile, cfg.BaseFeeMultiplier), nil
+	case GasPricerNode:
+		return gasprice.NewNodeGasPricer(client), nil
+	case gasPricerStatic:
+		return gasprice.NewStaticGasPricer(
+			convert.MustFloatToWei(cfg.StaticMaxGasPriceGwei, gweiDecimals),
+			convert.MustFloatToWei(cfg.StaticGasTipGwei, gweiDecimals),
+		), nil
+	default:
+		return nil, fmt.Errorf("invalid gas pricer: %s", name)
+	}
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/gasprice"
+)
+
+func TestNewGasPricer(t *testing.T) {
+	gasPricer, err := NewGasPricer(config.Config{}, nil, "")
+	require.NoError(t, err)
+	require.IsType(t, &gasprice.MetamaskGasPricer{}, gasPricer)
+
+	_, err = NewGasPricer(config.Config{}, nil, "urgent")
+	require.Error(t, err)
+
+	_, err = NewGasPricer(config.Config{GasPricer: "oracle"}, nil, "")
+	require.ErrorContains(t, err, "invalid gas pricer")
+
+	gasPricer, err = NewGasPricer(config.Config{GasPricer: GasPricerNode}, nil, "")
+	require.NoError(t, err)
+	require.IsType(t, &gasprice.NodeGasPricer{}, gasPricer)
+
+	cfg := config.Config{
+		GasPricers:            []string{gasPricerFeeHistory, gasPricerStatic},
+		StaticMaxGasPriceGwei: 30,
+		StaticGasTipGwei:      2,
+	}
+	gasPricer, err = NewGasPricer(cfg, nil, "")
+	require.NoError(t, err)
+	require.IsType(t, &gasprice.FallbackGasPricer{}, gasPricer)
+
+	cfg.GasPricers = []string{gasPricerStatic}
+	gasPricer, err = NewGasPricer(cfg, nil, "")
+	require.NoError(t, err)
+
+	quote, err := gasPricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(30e9), quote.MaxFee)
+	require.Equal(t, big.NewInt(2e9), quote.TipCap)
+}
+
+func TestNewTierGasPricers(t *testing.T) {
+	cfg := config.Config{
+		Accounts: []config.Account{
+			{GasTier: "low"},
+			{},
+			{GasTier: "low"},
+			{GasTier: "high"},
+		},
+	}
+
+	gasPricers, err := NewTierGasPricers(cfg, nil)
+	require.NoError(t, err)
+	require.Len(t, gasPricers, 2)
+	require.Contains(t, gasPricers, "low")
+	require.Contains(t, gasPricers, "high")
+
+	cfg.Accounts[0].GasTier = "urgent"
+	_, err = NewTierGasPricers(cfg, nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/hiepnv90/ilo/internal/clients/flashbots"
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/trader"
+)
+
+const (
+	// BroadcasterBundle sends transactions as a bundle through relay_url.
+	BroadcasterBundle = "bundle"
+
+	txTypeAuto    = "auto"
+	txTypeLegacy  = "legacy"
+	txTypeDynamic = "dynamic"
+)
+
+// UseLegacyTx reports whether tx_type selects legacy transactions, asking the
+// node when it is auto.
+func UseLegacyTx(ctx context.Context, cfg config.Config, client trader.HeaderClient) (bool, error) {
+	switch cfg.TxType {
+	case "", txTypeDynamic:
+		return false, nil
+	case txTypeLegacy:
+		return true, nil
+	case txTypeAuto:
+		dynamic, err := trader.SupportsDynamicFeeTx(ctx, client)
+		return !dynamic, err
+	default:
+		return false, fmt.Errorf("invalid tx type: %s", cfg.TxType)
+	}
+}
+
+// NewBundleBroadcaster creates the bundle broadcaster of relay_url, signing
+// relay requests with relay_auth_key or a random key when it is not set.
+func NewBundleBroadcaster(cfg config.Config, client trader.ChainClient) (*trader.BundleBroadcaster, error) {
+	var authKey *ecdsa.PrivateKey
+	var err error
+	if cfg.RelayAuthKey != "" {
+		authKey, err = crypto.HexToECDSA(cfg.RelayAuthKey)
+	} else {
+		authKey, err = crypto.GenerateKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("relay auth key: %w", err)
+	}
+
+	relay, err := flashbots.NewClient(cfg.RelayURL, authKey, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return trader.NewBundleBroadcaster(relay, client, cfg.BundleBlocks, !cfg.SkipBundleSimulation), nil
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+type fakeHeaderClient struct {
+	baseFee *big.Int
+}
+
+func (c fakeHeaderClient) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: c.baseFee}, nil
+}
+
+func TestUseLegacyTx(t *testing.T) {
+	dynamicNode := fakeHeaderClient{baseFee: big.NewInt(1e9)}
+	for _, tc := range []struct {
+		txType string
+		client fakeHeaderClient
+		legacy bool
+	}{
+		{txType: "", client: fakeHeaderClient{}, legacy: false},
+		{txType: txTypeDynamic, client: fakeHeaderClient{}, legacy: false},
+		{txType: txTypeLegacy, client: dynamicNode, legacy: true},
+		{txType: txTypeAuto, client: dynamicNode, legacy: false},
+		{txType: txTypeAuto, client: fakeHeaderClient{}, legacy: true},
+	} {
+		legacy, err := UseLegacyTx(context.Background(), config.Config{TxType: tc.txType}, tc.client)
+		require.NoError(t, err)
+		require.Equal(t, tc.legacy, legacy, tc.txType)
+	}
+
+	_, err := UseLegacyTx(context.Background(), config.Config{TxType: "blob"}, dynamicNode)
+	require.ErrorContains(t, err, "invalid tx type")
+}
+
+func TestNewBundleBroadcaster(t *testing.T) {
+	cfg := config.Config{RelayURL: "https://relay.flashbots.net"}
+	_, err := NewBundleBroadcaster(cfg, nil)
+	require.NoError(t, err)
+
+	cfg.RelayAuthKey = "not a key"
+	_, err = NewBundleBroadcaster(cfg, nil)
+	require.ErrorContains(t, err, "relay auth key")
+}
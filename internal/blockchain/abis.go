@@ -10,6 +10,16 @@ import (
 var (
 	uniswapV3RouterABI   abi.ABI
 	uniswapV3Router02ABI abi.ABI
+	uniswapV3FactoryABI  abi.ABI
+	uniswapV3PoolABI     abi.ABI
+	erc20ABI             abi.ABI
+	uniswapV3QuoterV2ABI abi.ABI
+	permit2ABI           abi.ABI
+	universalRouterABI   abi.ABI
+	uniswapV2Router02ABI abi.ABI
+	uniswapV2PairABI     abi.ABI
+	aerodromeRouterABI   abi.ABI
+	aerodromePoolABI     abi.ABI
 )
 
 //nolint:gochecknoinits
@@ -20,6 +30,16 @@ func init() {
 	}{
 		{&uniswapV3RouterABI, uniswapV3RouterJSON},
 		{&uniswapV3Router02ABI, uniswapV3Router02JSON},
+		{&uniswapV3FactoryABI, uniswapV3FactoryJSON},
+		{&uniswapV3PoolABI, uniswapV3PoolJSON},
+		{&erc20ABI, erc20JSON},
+		{&uniswapV3QuoterV2ABI, uniswapV3QuoterV2JSON},
+		{&permit2ABI, permit2JSON},
+		{&universalRouterABI, universalRouterJSON},
+		{&uniswapV2Router02ABI, uniswapV2Router02JSON},
+		{&uniswapV2PairABI, uniswapV2PairJSON},
+		{&aerodromeRouterABI, aerodromeRouterJSON},
+		{&aerodromePoolABI, aerodromePoolJSON},
 	}
 
 	for _, b := range builder {
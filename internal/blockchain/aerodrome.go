@@ -0,0 +1,83 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// AerodromeRoute is a hop of an Aerodrome swap through the stable or volatile
+// pool of From and To deployed by Factory, the zero factory being the
+// default factory of the router.
+type AerodromeRoute struct {
+	From    common.Address
+	To      common.Address
+	Stable  bool
+	Factory common.Address
+}
+
+// EncodeAerodromeSwapExactETHForTokens encodes an Aerodrome router swap of
+// the ETH sent along routes. The fee on transfer variant supports tokens
+// taking a fee on transfers but returns no amounts.
+func EncodeAerodromeSwapExactETHForTokens(
+	minOutputAmount *big.Int, routes []AerodromeRoute, recipient common.Address, deadline int64, feeOnTransfer bool,
+) ([]byte, error) {
+	return aerodromeRouterABI.Pack(
+		v2SwapMethod(methodSwapExactETHForTokens, feeOnTransfer),
+		minOutputAmount, routes, recipient, big.NewInt(deadline),
+	)
+}
+
+func EncodeAerodromeSwapExactTokensForTokens(
+	inputAmount, minOutputAmount *big.Int, routes []AerodromeRoute, recipient common.Address, deadline int64,
+	feeOnTransfer bool,
+) ([]byte, error) {
+	return aerodromeRouterABI.Pack(
+		v2SwapMethod(methodSwapExactTokensForTokens, feeOnTransfer),
+		inputAmount, minOutputAmount, routes, recipient, big.NewInt(deadline),
+	)
+}
+
+func EncodeAerodromeSwapExactTokensForETH(
+	inputAmount, minOutputAmount *big.Int, routes []AerodromeRoute, recipient common.Address, deadline int64,
+	feeOnTransfer bool,
+) ([]byte, error) {
+	return aerodromeRouterABI.Pack(
+		v2SwapMethod(methodSwapExactTokensForETH, feeOnTransfer),
+		inputAmount, minOutputAmount, routes, recipient, big.NewInt(deadline),
+	)
+}
+
+// EncodeAerodromeGetAmountsOut encodes a quote of routes, whose result is
+// decoded by DecodeAmounts like the swaps.
+func EncodeAerodromeGetAmountsOut(inputAmount *big.Int, routes []AerodromeRoute) ([]byte, error) {
+	return aerodromeRouterABI.Pack(methodGetAmountsOut, inputAmount, routes)
+}
+
+// AerodromeSwapTopic returns the topic of the Aerodrome pool Swap event,
+// which indexes the recipient and so differs from the V2 pair one.
+func AerodromeSwapTopic() common.Hash {
+	return aerodromePoolABI.Events[eventSwap].ID
+}
+
+// DecodeAerodromeSwap decodes an Aerodrome pool Swap log into a SwapEvent,
+// the amounts being what was paid to the pool minus what it paid out.
+func DecodeAerodromeSwap(log types.Log) (SwapEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != AerodromeSwapTopic() {
+		return SwapEvent{}, fmt.Errorf("not an aerodrome %s log", eventSwap)
+	}
+
+	var event v2SwapEvent
+	if err := aerodromePoolABI.UnpackIntoInterface(&event, eventSwap, log.Data); err != nil {
+		return SwapEvent{}, err
+	}
+
+	return SwapEvent{
+		Sender:    common.BytesToAddress(log.Topics[1].Bytes()),
+		Recipient: common.BytesToAddress(log.Topics[2].Bytes()),
+		Amount0:   new(big.Int).Sub(event.Amount0In, event.Amount0Out),
+		Amount1:   new(big.Int).Sub(event.Amount1In, event.Amount1Out),
+	}, nil
+}
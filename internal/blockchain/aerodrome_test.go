@@ -0,0 +1,79 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeAerodromeSwaps(t *testing.T) {
+	routes := []AerodromeRoute{
+		{
+			From: common.HexToAddress("0x4200000000000000000000000000000000000006"),
+			To:   common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+		},
+		{
+			From:    common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+			To:      common.HexToAddress("0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb"),
+			Stable:  true,
+			Factory: common.HexToAddress("0x420DD381b31aEf6683db6B902084cB0FFECe40Da"),
+		},
+	}
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	data, err := EncodeAerodromeSwapExactETHForTokens(big.NewInt(1), routes, recipient, 1_700_000_000, false)
+	require.NoError(t, err)
+	require.Equal(t, "0x903638a4", hexutil.Encode(data[:4]))
+
+	args, err := aerodromeRouterABI.Methods[methodSwapExactETHForTokens].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, routes, *abi.ConvertType(args[1], new([]AerodromeRoute)).(*[]AerodromeRoute))
+	require.Equal(t, recipient, args[2])
+
+	data, err = EncodeAerodromeSwapExactTokensForTokens(big.NewInt(1e18), big.NewInt(1), routes, recipient, 1_700_000_000, false)
+	require.NoError(t, err)
+	require.Equal(t, "0xcac88ea9", hexutil.Encode(data[:4]))
+
+	data, err = EncodeAerodromeGetAmountsOut(big.NewInt(1e18), routes)
+	require.NoError(t, err)
+	require.Equal(t, "0x5509a1ac", hexutil.Encode(data[:4]))
+}
+
+func TestDecodeAerodromeSwap(t *testing.T) {
+	require.Equal(t,
+		common.HexToHash("0xb3e2773606abfd36b5bd91394b3a54d1398336c65005baf7bf7a05efeffaf75b"), AerodromeSwapTopic())
+
+	// Swap of 1 WETH for 2500 USDC by the router through the WETH/USDC
+	// volatile pool on Base.
+	sender := common.HexToAddress("0xcF77a3Ba9A5CA399B7c97c74d54e5b1Beb874E43")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	swapLog := types.Log{
+		Address: common.HexToAddress("0xcDAC0d6c6C59727a65F871236188350531885C43"),
+		Topics: []common.Hash{
+			common.HexToHash("0xb3e2773606abfd36b5bd91394b3a54d1398336c65005baf7bf7a05efeffaf75b"),
+			common.HexToHash("0x000000000000000000000000cf77a3ba9a5ca399b7c97c74d54e5b1beb874e43"),
+			common.HexToHash("0x0000000000000000000000002c7536e3605d9c16a7a3d7b1898e529396a65c23"),
+		},
+		Data: common.FromHex("0x" +
+			"0000000000000000000000000000000000000000000000000de0b6b3a7640000" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"0000000000000000000000000000000000000000000000000000000000000000" +
+			"000000000000000000000000000000000000000000000000000000009502f900"),
+	}
+
+	event, err := DecodeAerodromeSwap(swapLog)
+	require.NoError(t, err)
+	require.Equal(t, sender, event.Sender)
+	require.Equal(t, recipient, event.Recipient)
+	require.Equal(t, big.NewInt(1e18), event.Amount0)
+	require.Equal(t, big.NewInt(-2500_000000), event.Amount1)
+
+	// The V2 decoder does not accept it.
+	_, err = DecodeV2Swap(swapLog)
+	require.Error(t, err)
+}
@@ -7,3 +7,33 @@ var uniswapV3RouterJSON []byte
 
 //go:embed abis/UniswapV3Router02.abi.json
 var uniswapV3Router02JSON []byte
+
+//go:embed abis/UniswapV3Factory.abi.json
+var uniswapV3FactoryJSON []byte
+
+//go:embed abis/UniswapV3Pool.abi.json
+var uniswapV3PoolJSON []byte
+
+//go:embed abis/ERC20.abi.json
+var erc20JSON []byte
+
+//go:embed abis/UniswapV3QuoterV2.abi.json
+var uniswapV3QuoterV2JSON []byte
+
+//go:embed abis/Permit2.abi.json
+var permit2JSON []byte
+
+//go:embed abis/UniversalRouter.abi.json
+var universalRouterJSON []byte
+
+//go:embed abis/UniswapV2Router02.abi.json
+var uniswapV2Router02JSON []byte
+
+//go:embed abis/UniswapV2Pair.abi.json
+var uniswapV2PairJSON []byte
+
+//go:embed abis/AerodromeRouter.abi.json
+var aerodromeRouterJSON []byte
+
+//go:embed abis/AerodromePool.abi.json
+var aerodromePoolJSON []byte
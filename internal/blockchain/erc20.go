@@ -0,0 +1,73 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	methodDecimals  = "decimals"
+	methodAllowance = "allowance"
+	methodApprove   = "approve"
+
+	eventTransfer = "Transfer"
+)
+
+type TransferEvent struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+}
+
+func EncodeDecimals() ([]byte, error) {
+	return erc20ABI.Pack(methodDecimals)
+}
+
+func DecodeDecimals(data []byte) (uint8, error) {
+	var decimals uint8
+	if err := erc20ABI.UnpackIntoInterface(&decimals, methodDecimals, data); err != nil {
+		return 0, err
+	}
+
+	return decimals, nil
+}
+
+func EncodeAllowance(owner, spender common.Address) ([]byte, error) {
+	return erc20ABI.Pack(methodAllowance, owner, spender)
+}
+
+func DecodeAllowance(data []byte) (*big.Int, error) {
+	var allowance *big.Int
+	if err := erc20ABI.UnpackIntoInterface(&allowance, methodAllowance, data); err != nil {
+		return nil, err
+	}
+
+	return allowance, nil
+}
+
+func EncodeApprove(spender common.Address, amount *big.Int) ([]byte, error) {
+	return erc20ABI.Pack(methodApprove, spender, amount)
+}
+
+// TransferTopic returns the topic of the ERC20 Transfer event.
+func TransferTopic() common.Hash {
+	return erc20ABI.Events[eventTransfer].ID
+}
+
+func DecodeTransfer(log types.Log) (TransferEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != TransferTopic() {
+		return TransferEvent{}, fmt.Errorf("not a %s log", eventTransfer)
+	}
+
+	var event TransferEvent
+	if err := erc20ABI.UnpackIntoInterface(&event, eventTransfer, log.Data); err != nil {
+		return TransferEvent{}, err
+	}
+	event.From = common.BytesToAddress(log.Topics[1].Bytes())
+	event.To = common.BytesToAddress(log.Topics[2].Bytes())
+
+	return event, nil
+}
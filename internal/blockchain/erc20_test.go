@@ -0,0 +1,48 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTransfer(t *testing.T) {
+	from := common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	to := common.HexToAddress("0x719911dCe2e792b93D74370c188f0E4AEc0860ec")
+	data, err := erc20ABI.Events[eventTransfer].Inputs.NonIndexed().Pack(big.NewInt(7000_000000))
+	require.NoError(t, err)
+
+	event, err := DecodeTransfer(types.Log{
+		Topics: []common.Hash{
+			TransferTopic(),
+			common.BytesToHash(from.Bytes()),
+			common.BytesToHash(to.Bytes()),
+		},
+		Data: data,
+	})
+	require.NoError(t, err)
+	require.Equal(t, from, event.From)
+	require.Equal(t, to, event.To)
+	require.Equal(t, big.NewInt(7000_000000), event.Value)
+
+	_, err = DecodeTransfer(types.Log{Topics: []common.Hash{SwapTopic()}})
+	require.Error(t, err)
+}
+
+func TestEncodeApprove(t *testing.T) {
+	spender := common.HexToAddress("0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45")
+	data, err := EncodeApprove(spender, big.NewInt(1000_000000))
+	require.NoError(t, err)
+
+	args, err := erc20ABI.Methods[methodApprove].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, spender, args[0])
+	require.Equal(t, big.NewInt(1000_000000), args[1])
+
+	allowance, err := DecodeAllowance(common.LeftPadBytes(big.NewInt(42).Bytes(), 32))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(42), allowance)
+}
@@ -0,0 +1,115 @@
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	methodPermit2Allowance = "allowance"
+
+	permit2Name = "Permit2"
+)
+
+//nolint:gochecknoglobals
+var (
+	eip712DomainTypeHash = crypto.Keccak256Hash(
+		[]byte("EIP712Domain(string name,uint256 chainId,address verifyingContract)"))
+	permitDetailsTypeHash = crypto.Keccak256Hash(
+		[]byte("PermitDetails(address token,uint160 amount,uint48 expiration,uint48 nonce)"))
+	permitSingleTypeHash = crypto.Keccak256Hash(
+		[]byte("PermitSingle(PermitDetails details,address spender,uint256 sigDeadline)" +
+			"PermitDetails(address token,uint160 amount,uint48 expiration,uint48 nonce)"))
+)
+
+// Permit2Allowance is the allowance an owner gave a spender through Permit2.
+type Permit2Allowance struct {
+	Amount     *big.Int
+	Expiration *big.Int
+	Nonce      *big.Int
+}
+
+type PermitDetails struct {
+	Token      common.Address
+	Amount     *big.Int
+	Expiration *big.Int
+	Nonce      *big.Int
+}
+
+// PermitSingle allows the spender to spend a token of the signer through
+// Permit2 until the expiration, once the signature is submitted before
+// SigDeadline.
+type PermitSingle struct {
+	Details     PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+func EncodePermit2Allowance(owner, token, spender common.Address) ([]byte, error) {
+	return permit2ABI.Pack(methodPermit2Allowance, owner, token, spender)
+}
+
+func DecodePermit2Allowance(data []byte) (Permit2Allowance, error) {
+	var allowance Permit2Allowance
+	if err := permit2ABI.UnpackIntoInterface(&allowance, methodPermit2Allowance, data); err != nil {
+		return Permit2Allowance{}, err
+	}
+
+	return allowance, nil
+}
+
+// Hash returns the EIP-712 digest of the permit to be signed by the owner.
+func (p PermitSingle) Hash(chainID *big.Int, permit2 common.Address) common.Hash {
+	domainSeparator := crypto.Keccak256(
+		eip712DomainTypeHash.Bytes(),
+		crypto.Keccak256([]byte(permit2Name)),
+		common.LeftPadBytes(chainID.Bytes(), 32),
+		common.LeftPadBytes(permit2.Bytes(), 32),
+	)
+
+	detailsHash := crypto.Keccak256(
+		permitDetailsTypeHash.Bytes(),
+		common.LeftPadBytes(p.Details.Token.Bytes(), 32),
+		common.LeftPadBytes(p.Details.Amount.Bytes(), 32),
+		common.LeftPadBytes(p.Details.Expiration.Bytes(), 32),
+		common.LeftPadBytes(p.Details.Nonce.Bytes(), 32),
+	)
+	structHash := crypto.Keccak256(
+		permitSingleTypeHash.Bytes(),
+		detailsHash,
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		common.LeftPadBytes(p.SigDeadline.Bytes(), 32),
+	)
+
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator, structHash)
+}
+
+// permitSingleArguments encodes a PermitSingle and its signature the way the
+// Universal Router PERMIT2_PERMIT command expects them.
+//
+//nolint:gochecknoglobals
+var permitSingleArguments = abi.Arguments{
+	{Type: mustNewType("tuple", []abi.ArgumentMarshaling{
+		{Name: "details", Type: "tuple", Components: []abi.ArgumentMarshaling{
+			{Name: "token", Type: "address"},
+			{Name: "amount", Type: "uint160"},
+			{Name: "expiration", Type: "uint48"},
+			{Name: "nonce", Type: "uint48"},
+		}},
+		{Name: "spender", Type: "address"},
+		{Name: "sigDeadline", Type: "uint256"},
+	})},
+	{Type: mustNewType("bytes", nil)},
+}
+
+func mustNewType(t string, components []abi.ArgumentMarshaling) abi.Type {
+	typ, err := abi.NewType(t, "", components)
+	if err != nil {
+		panic(err)
+	}
+
+	return typ
+}
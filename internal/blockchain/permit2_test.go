@@ -0,0 +1,76 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPermitSingleHash(t *testing.T) {
+	permit2 := common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+	permit := PermitSingle{
+		Details: PermitDetails{
+			Token:      common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"),
+			Amount:     big.NewInt(1000_000000),
+			Expiration: big.NewInt(1_700_000_000),
+			Nonce:      big.NewInt(3),
+		},
+		Spender:     common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"),
+		SigDeadline: big.NewInt(1_700_000_600),
+	}
+
+	want, _, err := apitypes.TypedDataAndHash(apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": {
+				{Name: "token", Type: "address"},
+				{Name: "amount", Type: "uint160"},
+				{Name: "expiration", Type: "uint48"},
+				{Name: "nonce", Type: "uint48"},
+			},
+		},
+		PrimaryType: "PermitSingle",
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           math.NewHexOrDecimal256(1),
+			VerifyingContract: permit2.Hex(),
+		},
+		Message: apitypes.TypedDataMessage{
+			"details": map[string]interface{}{
+				"token":      permit.Details.Token.Hex(),
+				"amount":     "1000000000",
+				"expiration": "1700000000",
+				"nonce":      "3",
+			},
+			"spender":     permit.Spender.Hex(),
+			"sigDeadline": "1700000600",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, common.BytesToHash(want), permit.Hash(big.NewInt(1), permit2))
+}
+
+func TestDecodePermit2Allowance(t *testing.T) {
+	data, err := permit2ABI.Methods[methodPermit2Allowance].Outputs.Pack(
+		big.NewInt(500), big.NewInt(1_700_000_000), big.NewInt(2))
+	require.NoError(t, err)
+
+	allowance, err := DecodePermit2Allowance(data)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(500), allowance.Amount)
+	require.Equal(t, big.NewInt(1_700_000_000), allowance.Expiration)
+	require.Equal(t, big.NewInt(2), allowance.Nonce)
+}
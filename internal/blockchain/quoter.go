@@ -0,0 +1,83 @@
+package blockchain
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	methodQuoteExactInputSingle = "quoteExactInputSingle"
+	methodQuoteExactInput       = "quoteExactInput"
+)
+
+type QuoteExactInputSingleParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	AmountIn          *big.Int
+	Fee               *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+type QuoteExactInputSingleResult struct {
+	AmountOut               *big.Int
+	SqrtPriceX96After       *big.Int
+	InitializedTicksCrossed uint32
+	GasEstimate             *big.Int
+}
+
+// EncodeQuoteExactInputSingle encodes a QuoterV2 quoteExactInputSingle call,
+// which must be executed with eth_call.
+func EncodeQuoteExactInputSingle(
+	inputToken common.Address,
+	outputToken common.Address,
+	inputAmount *big.Int,
+	fee *big.Int,
+) ([]byte, error) {
+	return uniswapV3QuoterV2ABI.Pack(
+		methodQuoteExactInputSingle,
+		QuoteExactInputSingleParams{
+			TokenIn:           inputToken,
+			TokenOut:          outputToken,
+			AmountIn:          inputAmount,
+			Fee:               fee,
+			SqrtPriceLimitX96: big.NewInt(0),
+		},
+	)
+}
+
+func DecodeQuoteExactInputSingle(data []byte) (QuoteExactInputSingleResult, error) {
+	var res QuoteExactInputSingleResult
+	if err := uniswapV3QuoterV2ABI.UnpackIntoInterface(&res, methodQuoteExactInputSingle, data); err != nil {
+		return QuoteExactInputSingleResult{}, err
+	}
+
+	return res, nil
+}
+
+type QuoteExactInputResult struct {
+	AmountOut                   *big.Int
+	SqrtPriceX96AfterList       []*big.Int
+	InitializedTicksCrossedList []uint32
+	GasEstimate                 *big.Int
+}
+
+// EncodeQuoteExactInput encodes a QuoterV2 quoteExactInput call along the
+// pools between tokens, which must be executed with eth_call.
+func EncodeQuoteExactInput(tokens []common.Address, fees []*big.Int, inputAmount *big.Int) ([]byte, error) {
+	path, err := EncodePath(tokens, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	return uniswapV3QuoterV2ABI.Pack(methodQuoteExactInput, path, inputAmount)
+}
+
+func DecodeQuoteExactInput(data []byte) (QuoteExactInputResult, error) {
+	var res QuoteExactInputResult
+	if err := uniswapV3QuoterV2ABI.UnpackIntoInterface(&res, methodQuoteExactInput, data); err != nil {
+		return QuoteExactInputResult{}, err
+	}
+
+	return res, nil
+}
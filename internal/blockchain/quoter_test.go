@@ -0,0 +1,57 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeQuoteExactInputSingle(t *testing.T) {
+	encodedData, err := EncodeQuoteExactInputSingle(
+		common.HexToAddress("0x4200000000000000000000000000000000000006"),
+		common.HexToAddress("0x6b9bb36519538e0c073894e964e90172e1c0b41f"),
+		big.NewInt(3000000000000000),
+		big.NewInt(10000),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "0xc6a5026a", hexutil.Encode(encodedData[:4]))
+	t.Log(hexutil.Encode(encodedData))
+}
+
+func TestDecodeQuoteExactInputSingle(t *testing.T) {
+	data, err := uniswapV3QuoterV2ABI.Methods[methodQuoteExactInputSingle].Outputs.Pack(
+		big.NewInt(7000_000000), big.NewInt(1), uint32(2), big.NewInt(90000))
+	require.NoError(t, err)
+
+	res, err := DecodeQuoteExactInputSingle(data)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7000_000000), res.AmountOut)
+	require.Equal(t, uint32(2), res.InitializedTicksCrossed)
+	require.Equal(t, big.NewInt(90000), res.GasEstimate)
+}
+
+func TestQuoteExactInput(t *testing.T) {
+	encodedData, err := EncodeQuoteExactInput(
+		[]common.Address{
+			common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+			common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"),
+			common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"),
+		},
+		[]*big.Int{big.NewInt(500), big.NewInt(10000)},
+		big.NewInt(1e18),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "0xcdca1753", hexutil.Encode(encodedData[:4]))
+
+	data, err := uniswapV3QuoterV2ABI.Methods[methodQuoteExactInput].Outputs.Pack(
+		big.NewInt(7000_000000), []*big.Int{big.NewInt(1), big.NewInt(2)}, []uint32{1, 3}, big.NewInt(150000))
+	require.NoError(t, err)
+
+	res, err := DecodeQuoteExactInput(data)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7000_000000), res.AmountOut)
+	require.Equal(t, []uint32{1, 3}, res.InitializedTicksCrossedList)
+}
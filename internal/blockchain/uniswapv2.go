@@ -0,0 +1,132 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	methodGetAmountsOut            = "getAmountsOut"
+	methodSwapExactETHForTokens    = "swapExactETHForTokens"
+	methodSwapExactTokensForTokens = "swapExactTokensForTokens"
+	methodSwapExactTokensForETH    = "swapExactTokensForETH"
+	methodSwapETHForExactTokens    = "swapETHForExactTokens"
+	methodSwapTokensForExactTokens = "swapTokensForExactTokens"
+	methodSwapTokensForExactETH    = "swapTokensForExactETH"
+
+	supportingFeeOnTransferTokens = "SupportingFeeOnTransferTokens"
+)
+
+type v2SwapEvent struct {
+	Amount0In  *big.Int
+	Amount1In  *big.Int
+	Amount0Out *big.Int
+	Amount1Out *big.Int
+}
+
+func v2SwapMethod(method string, feeOnTransfer bool) string {
+	if feeOnTransfer {
+		return method + supportingFeeOnTransferTokens
+	}
+
+	return method
+}
+
+// EncodeSwapExactETHForTokens encodes a V2 router swap of the ETH sent along
+// path. The fee on transfer variant supports tokens taking a fee on
+// transfers but returns no amounts.
+func EncodeSwapExactETHForTokens(
+	minOutputAmount *big.Int, path []common.Address, recipient common.Address, deadline int64, feeOnTransfer bool,
+) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(
+		v2SwapMethod(methodSwapExactETHForTokens, feeOnTransfer),
+		minOutputAmount, path, recipient, big.NewInt(deadline),
+	)
+}
+
+func EncodeSwapExactTokensForTokens(
+	inputAmount, minOutputAmount *big.Int, path []common.Address, recipient common.Address, deadline int64,
+	feeOnTransfer bool,
+) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(
+		v2SwapMethod(methodSwapExactTokensForTokens, feeOnTransfer),
+		inputAmount, minOutputAmount, path, recipient, big.NewInt(deadline),
+	)
+}
+
+func EncodeSwapExactTokensForETH(
+	inputAmount, minOutputAmount *big.Int, path []common.Address, recipient common.Address, deadline int64,
+	feeOnTransfer bool,
+) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(
+		v2SwapMethod(methodSwapExactTokensForETH, feeOnTransfer),
+		inputAmount, minOutputAmount, path, recipient, big.NewInt(deadline),
+	)
+}
+
+// EncodeSwapETHForExactTokens encodes a V2 router swap buying outputAmount
+// with the ETH sent, the unspent ETH being refunded.
+func EncodeSwapETHForExactTokens(
+	outputAmount *big.Int, path []common.Address, recipient common.Address, deadline int64,
+) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(
+		methodSwapETHForExactTokens, outputAmount, path, recipient, big.NewInt(deadline))
+}
+
+func EncodeSwapTokensForExactTokens(
+	outputAmount, maxInputAmount *big.Int, path []common.Address, recipient common.Address, deadline int64,
+) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(
+		methodSwapTokensForExactTokens, outputAmount, maxInputAmount, path, recipient, big.NewInt(deadline))
+}
+
+func EncodeSwapTokensForExactETH(
+	outputAmount, maxInputAmount *big.Int, path []common.Address, recipient common.Address, deadline int64,
+) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(
+		methodSwapTokensForExactETH, outputAmount, maxInputAmount, path, recipient, big.NewInt(deadline))
+}
+
+func EncodeGetAmountsOut(inputAmount *big.Int, path []common.Address) ([]byte, error) {
+	return uniswapV2Router02ABI.Pack(methodGetAmountsOut, inputAmount, path)
+}
+
+// DecodeAmounts decodes the amounts of every hop returned by getAmountsOut
+// and the V2 router swaps.
+func DecodeAmounts(data []byte) ([]*big.Int, error) {
+	var amounts []*big.Int
+	if err := uniswapV2Router02ABI.UnpackIntoInterface(&amounts, methodGetAmountsOut, data); err != nil {
+		return nil, err
+	}
+
+	return amounts, nil
+}
+
+// V2SwapTopic returns the topic of the V2 pair Swap event. Aerodrome pools
+// emit a different Swap event, see AerodromeSwapTopic.
+func V2SwapTopic() common.Hash {
+	return uniswapV2PairABI.Events[eventSwap].ID
+}
+
+// DecodeV2Swap decodes a V2 pair Swap log into a SwapEvent, the amounts
+// being what was paid to the pair minus what it paid out.
+func DecodeV2Swap(log types.Log) (SwapEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != V2SwapTopic() {
+		return SwapEvent{}, fmt.Errorf("not a v2 %s log", eventSwap)
+	}
+
+	var event v2SwapEvent
+	if err := uniswapV2PairABI.UnpackIntoInterface(&event, eventSwap, log.Data); err != nil {
+		return SwapEvent{}, err
+	}
+
+	return SwapEvent{
+		Sender:    common.BytesToAddress(log.Topics[1].Bytes()),
+		Recipient: common.BytesToAddress(log.Topics[2].Bytes()),
+		Amount0:   new(big.Int).Sub(event.Amount0In, event.Amount0Out),
+		Amount1:   new(big.Int).Sub(event.Amount1In, event.Amount1Out),
+	}, nil
+}
@@ -0,0 +1,72 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncodeV2Swaps(t *testing.T) {
+	path := []common.Address{
+		common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"),
+		common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"),
+	}
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	data, err := EncodeSwapExactETHForTokens(big.NewInt(1), path, recipient, 1_700_000_000, false)
+	require.NoError(t, err)
+	require.Equal(t, "0x7ff36ab5", hexutil.Encode(data[:4]))
+
+	data, err = EncodeSwapExactETHForTokens(big.NewInt(1), path, recipient, 1_700_000_000, true)
+	require.NoError(t, err)
+	require.Equal(t, "0xb6f9de95", hexutil.Encode(data[:4]))
+
+	data, err = EncodeSwapExactTokensForTokens(big.NewInt(1e18), big.NewInt(1), path, recipient, 1_700_000_000, false)
+	require.NoError(t, err)
+	require.Equal(t, "0x38ed1739", hexutil.Encode(data[:4]))
+
+	data, err = EncodeSwapExactTokensForTokens(big.NewInt(1e18), big.NewInt(1), path, recipient, 1_700_000_000, true)
+	require.NoError(t, err)
+	require.Equal(t, "0x5c11d795", hexutil.Encode(data[:4]))
+
+	args, err := uniswapV2Router02ABI.Methods[methodSwapExactTokensForTokens+supportingFeeOnTransferTokens].
+		Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, path, args[2])
+	require.Equal(t, recipient, args[3])
+}
+
+func TestDecodeAmounts(t *testing.T) {
+	data, err := uniswapV2Router02ABI.Methods[methodGetAmountsOut].Outputs.Pack(
+		[]*big.Int{big.NewInt(1e18), big.NewInt(2500_000000)})
+	require.NoError(t, err)
+
+	amounts, err := DecodeAmounts(data)
+	require.NoError(t, err)
+	require.Equal(t, []*big.Int{big.NewInt(1e18), big.NewInt(2500_000000)}, amounts)
+}
+
+func TestDecodeV2Swap(t *testing.T) {
+	data, err := uniswapV2PairABI.Events[eventSwap].Inputs.NonIndexed().Pack(
+		big.NewInt(0), big.NewInt(1e18), big.NewInt(2500_000000), big.NewInt(0))
+	require.NoError(t, err)
+
+	sender := common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	event, err := DecodeV2Swap(types.Log{
+		Topics: []common.Hash{
+			V2SwapTopic(),
+			common.BytesToHash(sender.Bytes()),
+			common.BytesToHash(recipient.Bytes()),
+		},
+		Data: data,
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(-2500_000000), event.Amount0)
+	require.Equal(t, big.NewInt(1e18), event.Amount1)
+	require.Equal(t, recipient, event.Recipient)
+}
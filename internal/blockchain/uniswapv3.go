@@ -1,13 +1,25 @@
 package blockchain
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 )
 
 const (
-	methodExactInputSingle = "exactInputSingle"
+	methodExactInputSingle  = "exactInputSingle"
+	methodExactInput        = "exactInput"
+	methodExactOutputSingle = "exactOutputSingle"
+	methodExactOutput       = "exactOutput"
+	methodRefundETH         = "refundETH"
+
+	methodUnwrapWETH9 = "unwrapWETH9" // unwrapWETH9(uint256,address)
+	methodSweepToken  = "sweepToken"  // sweepToken(address,uint256,address)
+
+	// Overloads of SwapRouter02 multicall are numbered in ABI order.
+	methodMulticall02             = "multicall1" // multicall(bytes[])
+	methodMulticallWithDeadline02 = "multicall0" // multicall(uint256,bytes[])
 )
 
 type ExactInputSingleParams struct {
@@ -76,3 +88,285 @@ func EncodeSwap02(
 		},
 	)
 }
+
+type ExactInputParams struct {
+	Path             []byte
+	Recipient        common.Address
+	Deadline         *big.Int
+	AmountIn         *big.Int
+	AmountOutMinimum *big.Int
+}
+
+// EncodeExactInput encodes a legacy router exactInput call swapping along
+// the pools between tokens, fees[i] being the fee of the tokens[i] to
+// tokens[i+1] pool.
+func EncodeExactInput(
+	tokens []common.Address,
+	fees []*big.Int,
+	recipient common.Address,
+	inputAmount *big.Int,
+	minOutputAmount *big.Int,
+	deadline int64,
+) ([]byte, error) {
+	path, err := EncodePath(tokens, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	return uniswapV3RouterABI.Pack(
+		methodExactInput,
+		ExactInputParams{
+			Path:             path,
+			Recipient:        recipient,
+			Deadline:         big.NewInt(deadline),
+			AmountIn:         inputAmount,
+			AmountOutMinimum: minOutputAmount,
+		},
+	)
+}
+
+type ExactInput02Params struct {
+	Path             []byte
+	Recipient        common.Address
+	AmountIn         *big.Int
+	AmountOutMinimum *big.Int
+}
+
+// EncodeExactInput02 is EncodeExactInput for SwapRouter02, which has no
+// deadline.
+func EncodeExactInput02(
+	tokens []common.Address,
+	fees []*big.Int,
+	recipient common.Address,
+	inputAmount *big.Int,
+	minOutputAmount *big.Int,
+) ([]byte, error) {
+	path, err := EncodePath(tokens, fees)
+	if err != nil {
+		return nil, err
+	}
+
+	return uniswapV3Router02ABI.Pack(
+		methodExactInput,
+		ExactInput02Params{
+			Path:             path,
+			Recipient:        recipient,
+			AmountIn:         inputAmount,
+			AmountOutMinimum: minOutputAmount,
+		},
+	)
+}
+
+type ExactOutputSingle02Params struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	Fee               *big.Int
+	Recipient         common.Address
+	AmountOut         *big.Int
+	AmountInMaximum   *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+// EncodeExactOutputSingle02 encodes a SwapRouter02 exactOutputSingle call
+// buying outputAmount while spending at most maxInputAmount.
+func EncodeExactOutputSingle02(
+	inputToken common.Address,
+	outputToken common.Address,
+	recipient common.Address,
+	outputAmount *big.Int,
+	maxInputAmount *big.Int,
+	fee *big.Int,
+) ([]byte, error) {
+	return uniswapV3Router02ABI.Pack(
+		methodExactOutputSingle,
+		ExactOutputSingle02Params{
+			TokenIn:           inputToken,
+			TokenOut:          outputToken,
+			Fee:               fee,
+			Recipient:         recipient,
+			AmountOut:         outputAmount,
+			AmountInMaximum:   maxInputAmount,
+			SqrtPriceLimitX96: big.NewInt(0),
+		},
+	)
+}
+
+type ExactOutput02Params struct {
+	Path            []byte
+	Recipient       common.Address
+	AmountOut       *big.Int
+	AmountInMaximum *big.Int
+}
+
+// EncodeExactOutput02 encodes a SwapRouter02 exactOutput call. Tokens and
+// fees are in swap order and packed in reverse, as exactOutput expects.
+func EncodeExactOutput02(
+	tokens []common.Address,
+	fees []*big.Int,
+	recipient common.Address,
+	outputAmount *big.Int,
+	maxInputAmount *big.Int,
+) ([]byte, error) {
+	reversedTokens := make([]common.Address, len(tokens))
+	for i, token := range tokens {
+		reversedTokens[len(tokens)-1-i] = token
+	}
+	reversedFees := make([]*big.Int, len(fees))
+	for i, fee := range fees {
+		reversedFees[len(fees)-1-i] = fee
+	}
+
+	path, err := EncodePath(reversedTokens, reversedFees)
+	if err != nil {
+		return nil, err
+	}
+
+	return uniswapV3Router02ABI.Pack(
+		methodExactOutput,
+		ExactOutput02Params{
+			Path:            path,
+			Recipient:       recipient,
+			AmountOut:       outputAmount,
+			AmountInMaximum: maxInputAmount,
+		},
+	)
+}
+
+// EncodeRefundETH encodes a refundETH call, which sends the ETH left in
+// the router back to the caller.
+func EncodeRefundETH() ([]byte, error) {
+	return uniswapV3Router02ABI.Pack(methodRefundETH)
+}
+
+// EncodeUnwrapWETH9 encodes an unwrapWETH9 call, which unwraps the whole
+// WETH balance of the router, reverting if it is below amountMin, and sends
+// the ETH to recipient.
+func EncodeUnwrapWETH9(amountMin *big.Int, recipient common.Address) ([]byte, error) {
+	return uniswapV3Router02ABI.Pack(methodUnwrapWETH9, amountMin, recipient)
+}
+
+// EncodeSweepToken encodes a sweepToken call, which sends the whole token
+// balance of the router, reverting if it is below amountMin, to recipient.
+func EncodeSweepToken(token common.Address, amountMin *big.Int, recipient common.Address) ([]byte, error) {
+	return uniswapV3Router02ABI.Pack(methodSweepToken, token, amountMin, recipient)
+}
+
+// EncodeMulticall02 encodes a SwapRouter02 multicall running calls in order.
+func EncodeMulticall02(calls [][]byte) ([]byte, error) {
+	return uniswapV3Router02ABI.Pack(methodMulticall02, calls)
+}
+
+// EncodeMulticallWithDeadline02 is EncodeMulticall02 reverting after
+// deadline.
+func EncodeMulticallWithDeadline02(deadline int64, calls [][]byte) ([]byte, error) {
+	return uniswapV3Router02ABI.Pack(methodMulticallWithDeadline02, big.NewInt(deadline), calls)
+}
+
+// DecodeMulticall02 decodes the results of the calls of a SwapRouter02
+// multicall.
+func DecodeMulticall02(data []byte) ([][]byte, error) {
+	var results [][]byte
+	if err := uniswapV3Router02ABI.UnpackIntoInterface(&results, methodMulticall02, data); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// Multicall02 accumulates SwapRouter02 calls to run in one transaction.
+type Multicall02 struct {
+	calls [][]byte
+}
+
+func (m *Multicall02) add(data []byte, err error) error {
+	if err != nil {
+		return err
+	}
+
+	m.calls = append(m.calls, data)
+	return nil
+}
+
+func (m *Multicall02) ExactInputSingle(
+	inputToken, outputToken, recipient common.Address, inputAmount, minOutputAmount, fee *big.Int,
+) error {
+	return m.add(EncodeSwap02(inputToken, outputToken, recipient, inputAmount, minOutputAmount, fee))
+}
+
+func (m *Multicall02) ExactInput(
+	tokens []common.Address, fees []*big.Int, recipient common.Address, inputAmount, minOutputAmount *big.Int,
+) error {
+	return m.add(EncodeExactInput02(tokens, fees, recipient, inputAmount, minOutputAmount))
+}
+
+func (m *Multicall02) ExactOutputSingle(
+	inputToken, outputToken, recipient common.Address, outputAmount, maxInputAmount, fee *big.Int,
+) error {
+	return m.add(EncodeExactOutputSingle02(inputToken, outputToken, recipient, outputAmount, maxInputAmount, fee))
+}
+
+func (m *Multicall02) ExactOutput(
+	tokens []common.Address, fees []*big.Int, recipient common.Address, outputAmount, maxInputAmount *big.Int,
+) error {
+	return m.add(EncodeExactOutput02(tokens, fees, recipient, outputAmount, maxInputAmount))
+}
+
+func (m *Multicall02) UnwrapWETH9(amountMin *big.Int, recipient common.Address) error {
+	return m.add(EncodeUnwrapWETH9(amountMin, recipient))
+}
+
+func (m *Multicall02) SweepToken(token common.Address, amountMin *big.Int, recipient common.Address) error {
+	return m.add(EncodeSweepToken(token, amountMin, recipient))
+}
+
+func (m *Multicall02) RefundETH() error {
+	return m.add(EncodeRefundETH())
+}
+
+// Encode encodes the calls as a multicall, or the call itself when there is
+// only one.
+func (m *Multicall02) Encode() ([]byte, error) {
+	if len(m.calls) == 1 {
+		return m.calls[0], nil
+	}
+
+	return EncodeMulticall02(m.calls)
+}
+
+// EncodeWithDeadline encodes the calls as a multicall reverting after
+// deadline.
+func (m *Multicall02) EncodeWithDeadline(deadline int64) ([]byte, error) {
+	return EncodeMulticallWithDeadline02(deadline, m.calls)
+}
+
+// EncodePath packs a Uniswap V3 path, the tokens interleaved with the 3 bytes
+// fee of the pool between each pair.
+func EncodePath(tokens []common.Address, fees []*big.Int) ([]byte, error) {
+	if len(tokens) < 2 || len(fees) != len(tokens)-1 {
+		return nil, fmt.Errorf("invalid path: %d tokens and %d fees", len(tokens), len(fees))
+	}
+
+	path := make([]byte, 0, len(tokens)*common.AddressLength+len(fees)*3)
+	for i, token := range tokens {
+		path = append(path, token.Bytes()...)
+		if i < len(fees) {
+			if fees[i].Sign() < 0 || fees[i].BitLen() > 24 {
+				return nil, fmt.Errorf("invalid fee: %v", fees[i])
+			}
+			path = append(path, common.LeftPadBytes(fees[i].Bytes(), 3)...)
+		}
+	}
+
+	return path, nil
+}
+
+// DecodeExactInputSingleOutput decodes the amountOut returned by
+// exactInputSingle or exactInput, which is the same for both routers.
+func DecodeExactInputSingleOutput(data []byte) (*big.Int, error) {
+	var amountOut *big.Int
+	if err := uniswapV3Router02ABI.UnpackIntoInterface(&amountOut, methodExactInputSingle, data); err != nil {
+		return nil, err
+	}
+
+	return amountOut, nil
+}
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/stretchr/testify/require"
@@ -36,3 +37,118 @@ func TestEncodeSwap02(t *testing.T) {
 	require.NoError(t, err)
 	t.Log(hexutil.Encode(encodedData))
 }
+
+func TestDecodeExactInputSingleOutput(t *testing.T) {
+	amountOut, err := DecodeExactInputSingleOutput(common.BigToHash(big.NewInt(7000_000000)).Bytes())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(7000_000000), amountOut)
+}
+
+func TestEncodePath(t *testing.T) {
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+
+	path, err := EncodePath([]common.Address{usdc, weth}, []*big.Int{big.NewInt(500)})
+	require.NoError(t, err)
+	require.Equal(t, common.FromHex(
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb480001f4c02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"), path)
+
+	_, err = EncodePath([]common.Address{usdc, weth}, nil)
+	require.Error(t, err)
+	_, err = EncodePath([]common.Address{usdc, weth}, []*big.Int{big.NewInt(1 << 24)})
+	require.Error(t, err)
+}
+
+func TestEncodeExactInput(t *testing.T) {
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	sale := common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	tokens := []common.Address{weth, usdc, sale}
+	fees := []*big.Int{big.NewInt(500), big.NewInt(10000)}
+
+	data, err := EncodeExactInput02(tokens, fees, recipient, big.NewInt(1e18), big.NewInt(1))
+	require.NoError(t, err)
+
+	args, err := uniswapV3Router02ABI.Methods[methodExactInput].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	params := *abi.ConvertType(args[0], new(ExactInput02Params)).(*ExactInput02Params)
+	path, err := EncodePath(tokens, fees)
+	require.NoError(t, err)
+	require.Equal(t, path, params.Path)
+	require.Equal(t, recipient, params.Recipient)
+
+	data, err = EncodeExactInput(tokens, fees, recipient, big.NewInt(1e18), big.NewInt(1), 1_700_000_000)
+	require.NoError(t, err)
+	require.Equal(t, uniswapV3RouterABI.Methods[methodExactInput].ID, data[:4])
+
+	_, err = EncodeExactInput02(tokens, fees[:1], recipient, big.NewInt(1e18), big.NewInt(1))
+	require.Error(t, err)
+}
+
+func TestEncodeExactOutput(t *testing.T) {
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	sale := common.HexToAddress("0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	data, err := EncodeExactOutput02(
+		[]common.Address{weth, usdc, sale}, []*big.Int{big.NewInt(500), big.NewInt(10000)},
+		recipient, big.NewInt(1000), big.NewInt(1e18))
+	require.NoError(t, err)
+
+	args, err := uniswapV3Router02ABI.Methods[methodExactOutput].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	params := *abi.ConvertType(args[0], new(ExactOutput02Params)).(*ExactOutput02Params)
+	path, err := EncodePath([]common.Address{sale, usdc, weth}, []*big.Int{big.NewInt(10000), big.NewInt(500)})
+	require.NoError(t, err)
+	require.Equal(t, path, params.Path)
+	require.Equal(t, big.NewInt(1000), params.AmountOut)
+	require.Equal(t, big.NewInt(1e18), params.AmountInMaximum)
+
+	swap, err := EncodeExactOutputSingle02(weth, sale, recipient, big.NewInt(1000), big.NewInt(1e18), big.NewInt(3000))
+	require.NoError(t, err)
+	refund, err := EncodeRefundETH()
+	require.NoError(t, err)
+	data, err = EncodeMulticall02([][]byte{swap, refund})
+	require.NoError(t, err)
+	require.Equal(t, "0xac9650d8", hexutil.Encode(data[:4])) // multicall(bytes[])
+}
+
+func TestMulticall02(t *testing.T) {
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	var multicall Multicall02
+	require.NoError(t, multicall.ExactInputSingle(
+		usdc, weth, RouterAddressThis, big.NewInt(2500_000000), big.NewInt(1e18), big.NewInt(500)))
+	single, err := multicall.Encode()
+	require.NoError(t, err)
+	require.Equal(t, uniswapV3Router02ABI.Methods[methodExactInputSingle].ID, single[:4])
+
+	require.NoError(t, multicall.UnwrapWETH9(big.NewInt(1e18), recipient))
+	require.NoError(t, multicall.SweepToken(usdc, big.NewInt(0), recipient))
+
+	data, err := multicall.EncodeWithDeadline(1_700_000_000)
+	require.NoError(t, err)
+	require.Equal(t, "0x5ae401dc", hexutil.Encode(data[:4])) // multicall(uint256,bytes[])
+
+	args, err := uniswapV3Router02ABI.Methods[methodMulticallWithDeadline02].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1_700_000_000), args[0])
+	calls := args[1].([][]byte)
+	require.Len(t, calls, 3)
+	require.Equal(t, single, calls[0])
+
+	unwrap, err := uniswapV3Router02ABI.Methods[methodUnwrapWETH9].Inputs.Unpack(calls[1][4:])
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1e18), unwrap[0])
+	require.Equal(t, recipient, unwrap[1])
+
+	results, err := uniswapV3Router02ABI.Methods[methodMulticall02].Outputs.Pack([][]byte{{1}, {}})
+	require.NoError(t, err)
+	decoded, err := DecodeMulticall02(results)
+	require.NoError(t, err)
+	require.Equal(t, [][]byte{{1}, {}}, decoded)
+}
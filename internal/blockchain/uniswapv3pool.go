@@ -0,0 +1,113 @@
+package blockchain
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	methodGetPool   = "getPool"
+	methodLiquidity = "liquidity"
+
+	eventPoolCreated = "PoolCreated"
+	eventMint        = "Mint"
+	eventSwap        = "Swap"
+)
+
+func EncodeGetPool(tokenA common.Address, tokenB common.Address, fee *big.Int) ([]byte, error) {
+	return uniswapV3FactoryABI.Pack(methodGetPool, tokenA, tokenB, fee)
+}
+
+func DecodeGetPool(data []byte) (common.Address, error) {
+	var pool common.Address
+	if err := uniswapV3FactoryABI.UnpackIntoInterface(&pool, methodGetPool, data); err != nil {
+		return common.Address{}, err
+	}
+
+	return pool, nil
+}
+
+func EncodeLiquidity() ([]byte, error) {
+	return uniswapV3PoolABI.Pack(methodLiquidity)
+}
+
+func DecodeLiquidity(data []byte) (*big.Int, error) {
+	var liquidity *big.Int
+	if err := uniswapV3PoolABI.UnpackIntoInterface(&liquidity, methodLiquidity, data); err != nil {
+		return nil, err
+	}
+
+	return liquidity, nil
+}
+
+// PoolCreatedTopic returns the topic of the factory PoolCreated event.
+func PoolCreatedTopic() common.Hash {
+	return uniswapV3FactoryABI.Events[eventPoolCreated].ID
+}
+
+// MintTopic returns the topic of the pool Mint event.
+func MintTopic() common.Hash {
+	return uniswapV3PoolABI.Events[eventMint].ID
+}
+
+// DecodePoolCreated returns the pool address of a PoolCreated log.
+func DecodePoolCreated(log types.Log) (common.Address, error) {
+	if len(log.Topics) == 0 || log.Topics[0] != PoolCreatedTopic() {
+		return common.Address{}, fmt.Errorf("not a %s log", eventPoolCreated)
+	}
+
+	var event struct {
+		TickSpacing *big.Int
+		Pool        common.Address
+	}
+	if err := uniswapV3FactoryABI.UnpackIntoInterface(&event, eventPoolCreated, log.Data); err != nil {
+		return common.Address{}, err
+	}
+
+	return event.Pool, nil
+}
+
+// SortTokens returns the tokens in the order used by uniswap pools.
+func SortTokens(tokenA common.Address, tokenB common.Address) (common.Address, common.Address) {
+	if bytes.Compare(tokenA.Bytes(), tokenB.Bytes()) < 0 {
+		return tokenA, tokenB
+	}
+
+	return tokenB, tokenA
+}
+
+type SwapEvent struct {
+	Sender       common.Address
+	Recipient    common.Address
+	Amount0      *big.Int
+	Amount1      *big.Int
+	SqrtPriceX96 *big.Int
+	Liquidity    *big.Int
+	Tick         *big.Int
+}
+
+// SwapTopic returns the topic of the pool Swap event.
+func SwapTopic() common.Hash {
+	return uniswapV3PoolABI.Events[eventSwap].ID
+}
+
+// DecodeSwap decodes a pool Swap log. Positive amounts are paid to the pool
+// and negative amounts are paid by the pool.
+func DecodeSwap(log types.Log) (SwapEvent, error) {
+	if len(log.Topics) != 3 || log.Topics[0] != SwapTopic() {
+		return SwapEvent{}, fmt.Errorf("not a %s log", eventSwap)
+	}
+
+	var event SwapEvent
+	if err := uniswapV3PoolABI.UnpackIntoInterface(&event, eventSwap, log.Data); err != nil {
+		return SwapEvent{}, err
+	}
+	event.Sender = common.BytesToAddress(log.Topics[1].Bytes())
+	event.Recipient = common.BytesToAddress(log.Topics[2].Bytes())
+
+	return event, nil
+}
@@ -0,0 +1,42 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSortTokens(t *testing.T) {
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	token0, token1 := SortTokens(weth, usdc)
+	require.Equal(t, usdc, token0)
+	require.Equal(t, weth, token1)
+}
+
+func TestDecodeSwap(t *testing.T) {
+	router := common.HexToAddress("0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45")
+	recipient := common.HexToAddress("0x719911dCe2e792b93D74370c188f0E4AEc0860ec")
+	data, err := uniswapV3PoolABI.Events[eventSwap].Inputs.NonIndexed().Pack(
+		big.NewInt(-7000_000000), big.NewInt(3e18), big.NewInt(1), big.NewInt(2), big.NewInt(-3))
+	require.NoError(t, err)
+
+	event, err := DecodeSwap(types.Log{
+		Topics: []common.Hash{
+			SwapTopic(),
+			common.BytesToHash(router.Bytes()),
+			common.BytesToHash(recipient.Bytes()),
+		},
+		Data: data,
+	})
+	require.NoError(t, err)
+	require.Equal(t, router, event.Sender)
+	require.Equal(t, recipient, event.Recipient)
+	require.Equal(t, big.NewInt(-7000_000000), event.Amount0)
+	require.Equal(t, big.NewInt(3e18), event.Amount1)
+	require.Equal(t, big.NewInt(-3), event.Tick)
+}
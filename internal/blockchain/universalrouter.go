@@ -0,0 +1,116 @@
+package blockchain
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	methodExecute = "execute"
+
+	commandV3SwapExactIn = 0x00
+	commandSweep         = 0x04
+	commandV2SwapExactIn = 0x08
+	commandPermit2Permit = 0x0a
+	commandWrapETH       = 0x0b
+	commandUnwrapWETH    = 0x0c
+)
+
+//nolint:gochecknoglobals
+var (
+	// RouterMsgSender and RouterAddressThis are recipients the Universal
+	// Router and SwapRouter02 replace with the caller and themselves.
+	RouterMsgSender   = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	RouterAddressThis = common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	v3SwapExactInArguments = abi.Arguments{
+		{Type: mustNewType("address", nil)}, // recipient
+		{Type: mustNewType("uint256", nil)}, // amountIn
+		{Type: mustNewType("uint256", nil)}, // amountOutMin
+		{Type: mustNewType("bytes", nil)},   // path
+		{Type: mustNewType("bool", nil)},    // payerIsUser
+	}
+	v2SwapExactInArguments = abi.Arguments{
+		{Type: mustNewType("address", nil)},   // recipient
+		{Type: mustNewType("uint256", nil)},   // amountIn
+		{Type: mustNewType("uint256", nil)},   // amountOutMin
+		{Type: mustNewType("address[]", nil)}, // path
+		{Type: mustNewType("bool", nil)},      // payerIsUser
+	}
+	sweepArguments = abi.Arguments{
+		{Type: mustNewType("address", nil)}, // token
+		{Type: mustNewType("address", nil)}, // recipient
+		{Type: mustNewType("uint256", nil)}, // amountMin
+	}
+	wethArguments = abi.Arguments{
+		{Type: mustNewType("address", nil)}, // recipient
+		{Type: mustNewType("uint256", nil)}, // amountMin
+	}
+)
+
+// UniversalRouterCommands accumulates the commands of a Universal Router
+// execute call in order.
+type UniversalRouterCommands struct {
+	commands []byte
+	inputs   [][]byte
+}
+
+func (c *UniversalRouterCommands) add(command byte, args abi.Arguments, values ...interface{}) error {
+	input, err := args.Pack(values...)
+	if err != nil {
+		return fmt.Errorf("encode command 0x%02x: %w", command, err)
+	}
+
+	c.commands = append(c.commands, command)
+	c.inputs = append(c.inputs, input)
+
+	return nil
+}
+
+// Permit2Permit submits a signed PermitSingle so that later commands can
+// pull the token from the signer through Permit2.
+func (c *UniversalRouterCommands) Permit2Permit(permit PermitSingle, signature []byte) error {
+	return c.add(commandPermit2Permit, permitSingleArguments, permit, signature)
+}
+
+// V3SwapExactIn swaps amountIn along a Uniswap V3 path. When payerIsUser is
+// set the input is pulled from the sender through Permit2, otherwise it is
+// paid from the router balance.
+func (c *UniversalRouterCommands) V3SwapExactIn(
+	recipient common.Address, amountIn, amountOutMin *big.Int, path []byte, payerIsUser bool,
+) error {
+	return c.add(commandV3SwapExactIn, v3SwapExactInArguments, recipient, amountIn, amountOutMin, path, payerIsUser)
+}
+
+// V2SwapExactIn swaps amountIn along a Uniswap V2 path of tokens.
+func (c *UniversalRouterCommands) V2SwapExactIn(
+	recipient common.Address, amountIn, amountOutMin *big.Int, path []common.Address, payerIsUser bool,
+) error {
+	return c.add(commandV2SwapExactIn, v2SwapExactInArguments, recipient, amountIn, amountOutMin, path, payerIsUser)
+}
+
+// WrapETH wraps amount of the ETH sent to the router into WETH.
+func (c *UniversalRouterCommands) WrapETH(recipient common.Address, amount *big.Int) error {
+	return c.add(commandWrapETH, wethArguments, recipient, amount)
+}
+
+// UnwrapWETH unwraps the whole WETH balance of the router, reverting if it is
+// below amountMin, and sends the ETH to recipient.
+func (c *UniversalRouterCommands) UnwrapWETH(recipient common.Address, amountMin *big.Int) error {
+	return c.add(commandUnwrapWETH, wethArguments, recipient, amountMin)
+}
+
+// Sweep sends the whole token balance of the router, reverting if it is
+// below amountMin, to recipient.
+func (c *UniversalRouterCommands) Sweep(token, recipient common.Address, amountMin *big.Int) error {
+	return c.add(commandSweep, sweepArguments, token, recipient, amountMin)
+}
+
+// EncodeExecute encodes the execute call running all commands, which
+// reverts after deadline.
+func (c *UniversalRouterCommands) EncodeExecute(deadline int64) ([]byte, error) {
+	return universalRouterABI.Pack(methodExecute, c.commands, c.inputs, big.NewInt(deadline))
+}
@@ -0,0 +1,77 @@
+package blockchain
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniversalRouterExecute(t *testing.T) {
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	path, err := EncodePath([]common.Address{usdc, weth}, []*big.Int{big.NewInt(500)})
+	require.NoError(t, err)
+
+	var commands UniversalRouterCommands
+	require.NoError(t, commands.Permit2Permit(PermitSingle{
+		Details: PermitDetails{
+			Token:      usdc,
+			Amount:     big.NewInt(1000_000000),
+			Expiration: big.NewInt(1_700_000_000),
+			Nonce:      big.NewInt(0),
+		},
+		Spender:     common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"),
+		SigDeadline: big.NewInt(1_700_000_000),
+	}, make([]byte, 65)))
+	require.NoError(t, commands.V3SwapExactIn(recipient, big.NewInt(1000_000000), big.NewInt(1), path, true))
+
+	data, err := commands.EncodeExecute(1_700_000_000)
+	require.NoError(t, err)
+
+	args, err := universalRouterABI.Methods[methodExecute].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, []byte{commandPermit2Permit, commandV3SwapExactIn}, args[0])
+
+	inputs := args[1].([][]byte)
+	require.Len(t, inputs, 2)
+	swap, err := v3SwapExactInArguments.Unpack(inputs[1])
+	require.NoError(t, err)
+	require.Equal(t, recipient, swap[0])
+	require.Equal(t, path, swap[3])
+	require.Equal(t, true, swap[4])
+}
+
+func TestUniversalRouterETH(t *testing.T) {
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+
+	var commands UniversalRouterCommands
+	require.NoError(t, commands.WrapETH(RouterAddressThis, big.NewInt(1e18)))
+	require.NoError(t, commands.V2SwapExactIn(
+		RouterAddressThis, big.NewInt(1e18), big.NewInt(1), []common.Address{weth, usdc}, false))
+	require.NoError(t, commands.Sweep(usdc, recipient, big.NewInt(1)))
+	require.NoError(t, commands.UnwrapWETH(RouterMsgSender, big.NewInt(0)))
+
+	data, err := commands.EncodeExecute(1_700_000_000)
+	require.NoError(t, err)
+
+	args, err := universalRouterABI.Methods[methodExecute].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, []byte{commandWrapETH, commandV2SwapExactIn, commandSweep, commandUnwrapWETH}, args[0])
+
+	inputs := args[1].([][]byte)
+	swap, err := v2SwapExactInArguments.Unpack(inputs[1])
+	require.NoError(t, err)
+	require.Equal(t, RouterAddressThis, swap[0])
+	require.Equal(t, []common.Address{weth, usdc}, swap[3])
+	require.Equal(t, false, swap[4])
+
+	sweep, err := sweepArguments.Unpack(inputs[2])
+	require.NoError(t, err)
+	require.Equal(t, usdc, sweep[0])
+	require.Equal(t, recipient, sweep[1])
+}
@@ -0,0 +1,212 @@
+package flashbots
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const signatureHeader = "X-Flashbots-Signature"
+
+// Client talks to a Flashbots compatible relay. Requests are signed with the
+// auth key, which only identifies the searcher and does not need to hold funds.
+type Client struct {
+	relayURL   *url.URL
+	authKey    *ecdsa.PrivateKey
+	httpClient *http.Client
+}
+
+func NewClient(relayURL string, authKey *ecdsa.PrivateKey, httpClient *http.Client) (*Client, error) {
+	u, err := url.Parse(relayURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse url: %w", err)
+	}
+
+	if authKey == nil {
+		return nil, errors.New("missing auth key")
+	}
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &Client{
+		relayURL:   u,
+		authKey:    authKey,
+		httpClient: httpClient,
+	}, nil
+}
+
+// SendBundle submits the transactions as a bundle targeting blockNumber.
+func (c *Client) SendBundle(
+	ctx context.Context, txs []*types.Transaction, blockNumber uint64,
+) (SendBundleResponse, error) {
+	rawTxs, err := encodeTransactions(txs)
+	if err != nil {
+		return SendBundleResponse{}, err
+	}
+
+	var res SendBundleResponse
+	err = c.call(ctx, "eth_sendBundle", SendBundleParams{
+		Txs:         rawTxs,
+		BlockNumber: hexutil.Uint64(blockNumber),
+	}, &res)
+	if err != nil {
+		return SendBundleResponse{}, err
+	}
+
+	return res, nil
+}
+
+// CallBundle simulates the transactions as a bundle in blockNumber on top of
+// the latest state.
+func (c *Client) CallBundle(
+	ctx context.Context, txs []*types.Transaction, blockNumber uint64,
+) (CallBundleResponse, error) {
+	rawTxs, err := encodeTransactions(txs)
+	if err != nil {
+		return CallBundleResponse{}, err
+	}
+
+	var res CallBundleResponse
+	err = c.call(ctx, "eth_callBundle", CallBundleParams{
+		Txs:              rawTxs,
+		BlockNumber:      hexutil.Uint64(blockNumber),
+		StateBlockNumber: "latest",
+	}, &res)
+	if err != nil {
+		return CallBundleResponse{}, err
+	}
+
+	return res, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	body, err := json.Marshal(jsonrpcRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  method,
+		Params:  []interface{}{params},
+	})
+	if err != nil {
+		return fmt.Errorf("encode request: %w", err)
+	}
+
+	signature, err := c.sign(body)
+	if err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.relayURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(signatureHeader, signature)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		data, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %s %s", errors.New("request failed"), resp.Status, data)
+	}
+
+	var rpcResp jsonrpcResponse
+	if err = json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	if rpcResp.Error != nil {
+		return fmt.Errorf("%s: %s", method, rpcResp.Error.Message)
+	}
+
+	if err = json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+
+	return nil
+}
+
+// sign returns the X-Flashbots-Signature header value of a request body.
+func (c *Client) sign(body []byte) (string, error) {
+	hash := accounts.TextHash([]byte(hexutil.Encode(crypto.Keccak256(body))))
+	signature, err := crypto.Sign(hash, c.authKey)
+	if err != nil {
+		return "", err
+	}
+
+	return crypto.PubkeyToAddress(c.authKey.PublicKey).Hex() + ":" + hexutil.Encode(signature), nil
+}
+
+func encodeTransactions(txs []*types.Transaction) ([]hexutil.Bytes, error) {
+	rawTxs := make([]hexutil.Bytes, 0, len(txs))
+	for _, tx := range txs {
+		data, err := tx.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("encode transaction: %w", err)
+		}
+		rawTxs = append(rawTxs, data)
+	}
+
+	return rawTxs, nil
+}
+
+type jsonrpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      int           `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+type jsonrpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonrpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *jsonrpcError   `json:"error"`
+}
+
+type SendBundleParams struct {
+	Txs         []hexutil.Bytes `json:"txs"`
+	BlockNumber hexutil.Uint64  `json:"blockNumber"`
+}
+
+type SendBundleResponse struct {
+	BundleHash common.Hash `json:"bundleHash"`
+}
+
+type CallBundleParams struct {
+	Txs              []hexutil.Bytes `json:"txs"`
+	BlockNumber      hexutil.Uint64  `json:"blockNumber"`
+	StateBlockNumber string          `json:"stateBlockNumber"`
+}
+
+type CallBundleResult struct {
+	TxHash  common.Hash `json:"txHash"`
+	GasUsed uint64      `json:"gasUsed"`
+	Error   string      `json:"error"`
+	Revert  string      `json:"revert"`
+}
+
+type CallBundleResponse struct {
+	BundleHash   common.Hash        `json:"bundleHash"`
+	TotalGasUsed uint64             `json:"totalGasUsed"`
+	Results      []CallBundleResult `json:"results"`
+}
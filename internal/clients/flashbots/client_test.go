@@ -0,0 +1,113 @@
+package flashbots
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+type relayRequest struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func newTestRelay(t *testing.T, results map[string]string) (*httptest.Server, *[]relayRequest) {
+	var requests []relayRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		// Verify the signature header against the body.
+		parts := strings.Split(r.Header.Get(signatureHeader), ":")
+		require.Len(t, parts, 2)
+		signature, err := hexutil.Decode(parts[1])
+		require.NoError(t, err)
+		hash := accounts.TextHash([]byte(hexutil.Encode(crypto.Keccak256(body))))
+		pub, err := crypto.SigToPub(hash, signature)
+		require.NoError(t, err)
+		require.Equal(t, common.HexToAddress(parts[0]), crypto.PubkeyToAddress(*pub))
+
+		var req relayRequest
+		require.NoError(t, json.Unmarshal(body, &req))
+		requests = append(requests, req)
+
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + results[req.Method] + `}`))
+	}))
+
+	return srv, &requests
+}
+
+func newTestTx(t *testing.T) *types.Transaction {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	tx, err := types.SignNewTx(key, types.LatestSignerForChainID(big.NewInt(1)), &types.DynamicFeeTx{
+		ChainID:   big.NewInt(1),
+		GasTipCap: big.NewInt(1),
+		GasFeeCap: big.NewInt(2),
+		Gas:       21000,
+	})
+	require.NoError(t, err)
+
+	return tx
+}
+
+func TestClientSendBundle(t *testing.T) {
+	srv, requests := newTestRelay(t, map[string]string{
+		"eth_sendBundle": `{"bundleHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}`,
+	})
+	defer srv.Close()
+
+	authKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	client, err := NewClient(srv.URL, authKey, nil)
+	require.NoError(t, err)
+
+	tx := newTestTx(t)
+	res, err := client.SendBundle(context.Background(), []*types.Transaction{tx}, 100)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToHash("0x1"), res.BundleHash)
+
+	require.Len(t, *requests, 1)
+	require.Equal(t, "eth_sendBundle", (*requests)[0].Method)
+	var params SendBundleParams
+	require.NoError(t, json.Unmarshal((*requests)[0].Params[0], &params))
+	require.Equal(t, hexutil.Uint64(100), params.BlockNumber)
+	rawTx, err := tx.MarshalBinary()
+	require.NoError(t, err)
+	require.Equal(t, []hexutil.Bytes{rawTx}, params.Txs)
+}
+
+func TestClientCallBundle(t *testing.T) {
+	srv, requests := newTestRelay(t, map[string]string{
+		"eth_callBundle": `{"results":[{"gasUsed":21000,"revert":"execution reverted"}]}`,
+	})
+	defer srv.Close()
+
+	authKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	client, err := NewClient(srv.URL, authKey, nil)
+	require.NoError(t, err)
+
+	res, err := client.CallBundle(context.Background(), []*types.Transaction{newTestTx(t)}, 100)
+	require.NoError(t, err)
+	require.Len(t, res.Results, 1)
+	require.Equal(t, uint64(21000), res.Results[0].GasUsed)
+	require.Equal(t, "execution reverted", res.Results[0].Revert)
+
+	var params CallBundleParams
+	require.NoError(t, json.Unmarshal((*requests)[0].Params[0], &params))
+	require.Equal(t, "latest", params.StateBlockNumber)
+}
@@ -0,0 +1,103 @@
+package krystal
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testSrcToken       = "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee"
+	testDstToken       = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	testPlatformWallet = "0x0000000000000000000000000000000000000001"
+	testUserAddress    = "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23"
+)
+
+func newTestServer(t *testing.T, path string, body string, query *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != path {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+
+		*query = r.URL.Query()
+		_, _ = w.Write([]byte(body))
+	}))
+}
+
+func TestClientGetAllRates(t *testing.T) {
+	var query url.Values
+	srv := newTestServer(t, "/swap/allRates", `{
+		"timestamp": 1723275906,
+		"rates": [
+			{"amount": "2500000000", "platform": "uniswapV3", "hint": "0x01", "estimatedGas": 150000},
+			{"amount": "2510000000", "platform": "kyberswap", "hint": "0x02", "estimatedGas": 180000}
+		]
+	}`, &query)
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, nil)
+	require.NoError(t, err)
+
+	res, err := client.GetAllRates(testSrcToken, testDstToken, big.NewInt(1e18), testPlatformWallet, testUserAddress)
+	require.NoError(t, err)
+	require.Len(t, res.Rates, 2)
+	require.Equal(t, "kyberswap", res.Rates[1].Platform)
+	require.Equal(t, uint64(180000), res.Rates[1].EstimatedGas)
+
+	require.Equal(t, testSrcToken, query.Get("src"))
+	require.Equal(t, testDstToken, query.Get("dest"))
+	require.Equal(t, "1000000000000000000", query.Get("srcAmount"))
+	require.Equal(t, testPlatformWallet, query.Get("platformWallet"))
+	require.Equal(t, testUserAddress, query.Get("userAddress"))
+}
+
+func TestClientBuildTx(t *testing.T) {
+	var query url.Values
+	srv := newTestServer(t, "/swap/buildTx", `{
+		"timestamp": 1723275906,
+		"txObject": {
+			"from": "0x2c7536e3605d9c16a7a3d7b1898e529396a65c23",
+			"to": "0x70270c228c5b4279d1578799926873aa72446ccd",
+			"value": "0xde0b6b3a7640000",
+			"data": "0x12345678"
+		}
+	}`, &query)
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, nil)
+	require.NoError(t, err)
+
+	res, err := client.BuildTx(
+		testSrcToken, testDstToken, big.NewInt(1e18), big.NewInt(2400_000000),
+		testPlatformWallet, testUserAddress, "0x02", big.NewInt(20e9), 7, true,
+	)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x70270c228c5b4279d1578799926873aa72446ccd"), res.TxObject.To)
+	require.Equal(t, big.NewInt(1e18), res.TxObject.Value.ToInt())
+	require.Equal(t, []byte{0x12, 0x34, 0x56, 0x78}, []byte(res.TxObject.Data))
+
+	require.Equal(t, "2400000000", query.Get("minDestAmount"))
+	require.Equal(t, "0x02", query.Get("hint"))
+	require.Equal(t, "20000000000", query.Get("gasPrice"))
+	require.Equal(t, "7", query.Get("nonce"))
+	require.Equal(t, "true", query.Get("skipBalanceCheck"))
+}
+
+func TestClientError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "token not supported", http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	client, err := NewClient(srv.URL, nil)
+	require.NoError(t, err)
+
+	_, err = client.GetAllRates(testSrcToken, testDstToken, big.NewInt(1e18), testPlatformWallet, testUserAddress)
+	require.ErrorContains(t, err, "token not supported")
+}
@@ -17,25 +17,196 @@ type Account struct {
 	MaxGasFee       *big.Int `yaml:"max_gas_fee"`
 	MinReturnAmount *big.Int `yaml:"min_return_amount"`
 
+	// ExactOutput makes amount the output amount to buy, spending at most
+	// MaxInputAmount.
+	ExactOutput    bool     `yaml:"exact_output"`
+	MaxInputAmount *big.Int `yaml:"max_input_amount"`
+
+	DeadlineSeconds int64 `yaml:"deadline_seconds"`
+
+	// GasTier overrides the Metamask gas tier of the config.
+	GasTier string `yaml:"gas_tier"`
+
 	PrivKey string `yaml:"priv_key"` // optional, set this empty to use keystore
 }
 
+// FeeTierAuto is the fee_tier "auto", resolved at startup to the fee tier of
+// the pool with the most liquidity or the best quote.
+const FeeTierAuto FeeTier = -1
+
+// FeeTier is a Uniswap V3 pool fee in hundredths of a bip, or FeeTierAuto.
+type FeeTier int64
+
+func (f *FeeTier) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil && s == "auto" {
+		*f = FeeTierAuto
+		return nil
+	}
+
+	var fee int64
+	if err := unmarshal(&fee); err != nil {
+		return err
+	}
+
+	*f = FeeTier(fee)
+	return nil
+}
+
+// AerodromeRoute is a hop of an Aerodrome swap through the stable or volatile
+// pool of From and To. Factory defaults to the default factory of the router.
+type AerodromeRoute struct {
+	From    string `yaml:"from"`
+	To      string `yaml:"to"`
+	Stable  bool   `yaml:"stable"`
+	Factory string `yaml:"factory"`
+}
+
 type Config struct {
-	ChainID           int64     `yaml:"chain_id"`
-	NodeRPC           string    `yaml:"node_rpc"`
-	GasPriceEndpoint  string    `yaml:"gas_price_endpoint"`
-	KeystoreDir       string    `yaml:"keystore_dir"`
-	RouterAddress     string    `yaml:"router_address"`
-	InputToken        string    `yaml:"input_token"`
-	OutputToken       string    `yaml:"output_token"`
-	FeeTier           int64     `yaml:"fee_tier"`
-	GasTipMultiplier  float64   `yaml:"gas_tip_multiplier"`
-	StartTime         time.Time `yaml:"start_time"`
-	GasLimit          int64     `yaml:"gas_limit"`
-	MinReturnAmount   *big.Int  `yaml:"min_return_amount"`
-	Weth              string    `yaml:"weth"`
+	ChainID          int64     `yaml:"chain_id"`
+	NodeRPC          string    `yaml:"node_rpc"`
+	NodeRPCs         []string  `yaml:"node_rpcs"`
+	SendRPCs         []string  `yaml:"send_rpcs"`
+	GasPriceEndpoint string    `yaml:"gas_price_endpoint"`
+	KeystoreDir      string    `yaml:"keystore_dir"`
+	RouterAddress    string    `yaml:"router_address"`
+	InputToken       string    `yaml:"input_token"`
+	OutputToken      string    `yaml:"output_token"`
+	FeeTier          FeeTier   `yaml:"fee_tier"`
+	GasTipMultiplier float64   `yaml:"gas_tip_multiplier"`
+	StartTime        time.Time `yaml:"start_time"`
+	GasLimit         int64     `yaml:"gas_limit"`
+	MinReturnAmount  *big.Int  `yaml:"min_return_amount"`
+	QuoterAddress    string    `yaml:"quoter_address"`
+	SlippageBPS      int64     `yaml:"slippage_bps"`
+	MaxPrice         float64   `yaml:"max_price"` // in input token per output token, exact input only
+	Weth             string    `yaml:"weth"`
+
+	// GasPricer is "metamask" (default), which reads gas_price_endpoint,
+	// "fee_history", which prices gas from the eth_feeHistory of the node:
+	// the GasPricePercentile reward as tip, 50 by default, on top of the next
+	// base fee times BaseFeeMultiplier, 2 by default, or "eth_gas_price",
+	// which prices legacy transactions with the eth_gasPrice of the node.
+	GasPricer          string  `yaml:"gas_pricer"`
+	GasPricePercentile float64 `yaml:"gas_price_percentile"`
+	BaseFeeMultiplier  float64 `yaml:"base_fee_multiplier"`
+
+	// TxType is "dynamic" (default) for EIP-1559 transactions, "legacy" for
+	// chains without EIP-1559, which are priced with eth_gasPrice unless
+	// GasPricer is set, or "auto" to pick by the base fee of the latest
+	// block.
+	TxType string `yaml:"tx_type"`
+
+	// GasTier is the Metamask gas fee suggestion used, "low", "medium" or
+	// "high" (default). AdaptiveGasTier escalates to the next tier, or adds a
+	// premium to the high tier, while the network is congested or priority
+	// fees are rising.
+	GasTier         string `yaml:"gas_tier"`
+	AdaptiveGasTier bool   `yaml:"adaptive_gas_tier"`
+
+	// GasPricers replaces GasPricer with pricers asked in order until one
+	// answers, "static" returning StaticMaxGasPriceGwei and StaticGasTipGwei.
+	// A failing pricer is skipped for GasPricerCooldownSeconds, 30 by
+	// default.
+	GasPricers               []string `yaml:"gas_pricers"`
+	StaticMaxGasPriceGwei    float64  `yaml:"static_max_gas_price_gwei"`
+	StaticGasTipGwei         float64  `yaml:"static_gas_tip_gwei"`
+	GasPricerCooldownSeconds int64    `yaml:"gas_pricer_cooldown_seconds"`
+
+	// FeeTiers are the custom fee tiers fee_tier auto tries along with the
+	// standard ones.
+	FeeTiers []int64 `yaml:"fee_tiers"`
+
+	// DeadlineSeconds makes swaps revert if not mined within that many
+	// seconds after they are signed, or after start_time when presigning.
+	// Presigning with a deadline is not supported with a chain trigger.
+	// Pending transactions are not waited for or replaced past the deadline.
+	DeadlineSeconds int64 `yaml:"deadline_seconds"`
+
+	// Dex is "uniswap_v3" (default), "uniswap_v2" for Uniswap V2 style
+	// routers, which swap along Path, or "aerodrome" for Aerodrome routers,
+	// which swap along AerodromeRoutes. Both quote with getAmountsOut and
+	// swap input_token to output_token directly by default, through the
+	// volatile pool on Aerodrome.
+	Dex             string           `yaml:"dex"`
+	Path            []string         `yaml:"path"`
+	AerodromeRoutes []AerodromeRoute `yaml:"aerodrome_routes"`
+	FeeOnTransfer   bool             `yaml:"fee_on_transfer"`
+
+	// Route swaps through several pools instead of the fee_tier pool, as
+	// tokens separated by pool fees, e.g. [WETH, 500, USDC, 10000, SALE].
+	Route []string `yaml:"route"`
+
+	// Routing is "direct" (default) to swap through router_address, or
+	// "krystal" to use the best Krystal rate and fall back to direct. Krystal
+	// routing only supports an ETH input.
+	Routing        string `yaml:"routing"`
+	KrystalAPI     string `yaml:"krystal_api"`
+	PlatformWallet string `yaml:"platform_wallet"`
+
+	// Approve is "exact" or "unlimited" to approve the spender, permit2_address
+	// or router_address by default, when the allowance of an ERC20 input token
+	// is too low.
+	Approve        string `yaml:"approve"`
+	ApproveSpender string `yaml:"approve_spender"`
+
+	// RouterType is "swap_router02" (default) or "universal" when
+	// router_address is a Universal Router.
+	RouterType string `yaml:"router_type"`
+
+	// Permit2Address makes accounts sign a Permit2 PermitSingle for the
+	// Universal Router instead of approving it with a transaction.
+	Permit2Address string `yaml:"permit2_address"`
+
 	Accounts          []Account `yaml:"accounts"`
 	SkipCheckTxStatus bool      `yaml:"skip_check_tx_status"`
+	Presign           bool      `yaml:"presign"`
+
+	// Replace pending transactions with bumped fees.
+	ReplaceAfterBlocks uint64 `yaml:"replace_after_blocks"`
+	ReplaceBumpPercent int64  `yaml:"replace_bump_percent"`
+	MaxReplacements    int    `yaml:"max_replacements"`
+
+	// Broadcaster is "rpc" (default) to send through node_rpc or "bundle" to
+	// send all transactions as a bundle to a Flashbots compatible relay.
+	Broadcaster          string `yaml:"broadcaster"`
+	RelayURL             string `yaml:"relay_url"`
+	RelayAuthKey         string `yaml:"relay_auth_key"` // optional, a random key is used if empty
+	BundleBlocks         uint64 `yaml:"bundle_blocks"`
+	SkipBundleSimulation bool   `yaml:"skip_bundle_simulation"`
+
+	// Start on a block or an on-chain event instead of start_time.
+	WSRPC                 string `yaml:"ws_rpc"`
+	StartBlock            uint64 `yaml:"start_block"`
+	StartOnPoolCreated    bool   `yaml:"start_on_pool_created"`
+	StartOnLiquidityAdded bool   `yaml:"start_on_liquidity_added"`
+	FactoryAddress        string `yaml:"factory_address"`
+}
+
+// NodeRPCURLs returns node_rpc and node_rpcs, which are used for reads.
+func (c Config) NodeRPCURLs() []string {
+	urls := c.NodeRPCs
+	if c.NodeRPC != "" {
+		urls = append([]string{c.NodeRPC}, urls...)
+	}
+
+	return urls
+}
+
+// SendRPCURLs returns the endpoints signed transactions are sent to, which
+// default to the read nodes.
+func (c Config) SendRPCURLs() []string {
+	if len(c.SendRPCs) > 0 {
+		return c.SendRPCs
+	}
+
+	return c.NodeRPCURLs()
+}
+
+// HasChainTrigger reports whether trading starts on a block or an on-chain
+// event rather than start_time.
+func (c Config) HasChainTrigger() bool {
+	return c.StartBlock > 0 || c.StartOnPoolCreated || c.StartOnLiquidityAdded
 }
 
 func LoadFromFile(fpath string) (Config, error) {
@@ -3,22 +3,43 @@ package gasprice
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"sync"
 	"time"
 )
 
 type GasPricer interface {
-	GasPrice(ctx context.Context) (float64, float64, error)
+	GasPrice(ctx context.Context) (GasQuote, error)
+}
+
+// GasQuote is a gas price suggestion in wei.
+type GasQuote struct {
+	BaseFee *big.Int // estimated base fee of the next block, nil if unknown
+	MaxFee  *big.Int
+	TipCap  *big.Int
+
+	// GasPrice is the price of legacy transactions, nil if unknown.
+	GasPrice *big.Int
+
+	// Source is the pricer the quote comes from and Timestamp the time it
+	// was fetched.
+	Source    string
+	Timestamp time.Time
+}
+
+// MulFloat returns amount times f, rounded down to the wei.
+func MulFloat(amount *big.Int, f float64) *big.Int {
+	product, _ := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(f)).Int(nil)
+	return product
 }
 
 type CacheGasPricer struct {
 	ttl     time.Duration
 	backend GasPricer
 
-	mu              sync.Mutex
-	expireAt        time.Time
-	maxGasPriceGwei float64
-	tipCapGwei      float64
+	mu       sync.Mutex
+	expireAt time.Time
+	quote    GasQuote
 }
 
 func NewCacheGasPricer(backend GasPricer, ttl time.Duration) *CacheGasPricer {
@@ -28,27 +49,26 @@ func NewCacheGasPricer(backend GasPricer, ttl time.Duration) *CacheGasPricer {
 	}
 }
 
-func (c *CacheGasPricer) GasPrice(ctx context.Context) (float64, float64, error) {
+func (c *CacheGasPricer) GasPrice(ctx context.Context) (GasQuote, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	if c.expireAt.Before(time.Now()) {
 		err := c.updateGasPrice(ctx)
 		if err != nil {
-			return 0, 0, fmt.Errorf("update gas price: %w", err)
+			return GasQuote{}, fmt.Errorf("update gas price: %w", err)
 		}
 	}
 
-	return c.maxGasPriceGwei, c.tipCapGwei, nil
+	return c.quote, nil
 }
 
 func (c *CacheGasPricer) updateGasPrice(ctx context.Context) error {
-	maxGasPriceGwei, tipCapGwei, err := c.backend.GasPrice(ctx)
+	quote, err := c.backend.GasPrice(ctx)
 	if err != nil {
 		return err
 	}
 
 	c.expireAt = time.Now().Add(c.ttl)
-	c.maxGasPriceGwei = maxGasPriceGwei
-	c.tipCapGwei = tipCapGwei
+	c.quote = quote
 	return nil
 }
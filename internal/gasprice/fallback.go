@@ -0,0 +1,111 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// FallbackGasPricer asks its backends in order until one answers. A backend
+// that fails is skipped for the cooldown period, unless all the others fail
+// too.
+type FallbackGasPricer struct {
+	names    []string
+	backends []GasPricer
+	cooldown time.Duration
+
+	mu          sync.Mutex
+	failedUntil []time.Time
+	source      string
+}
+
+func NewFallbackGasPricer(names []string, backends []GasPricer, cooldown time.Duration) *FallbackGasPricer {
+	return &FallbackGasPricer{
+		names:       names,
+		backends:    backends,
+		cooldown:    cooldown,
+		failedUntil: make([]time.Time, len(backends)),
+	}
+}
+
+// Source returns the name of the backend that answered last.
+func (p *FallbackGasPricer) Source() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.source
+}
+
+func (p *FallbackGasPricer) GasPrice(ctx context.Context) (GasQuote, error) {
+	var errs []error
+	for _, i := range p.order() {
+		quote, err := p.backends[i].GasPrice(ctx)
+		if err != nil {
+			log.Printf("Fail to get gas price: source=%s cooldown=%v error=%v", p.names[i], p.cooldown, err)
+			p.setFailed(i)
+			errs = append(errs, fmt.Errorf("%s: %w", p.names[i], err))
+			continue
+		}
+
+		p.setSource(i)
+		quote.Source = p.sourceOf(i, quote.Source)
+		return quote, nil
+	}
+
+	if len(errs) == 0 {
+		return GasQuote{}, errors.New("no gas pricer")
+	}
+
+	return GasQuote{}, errors.Join(errs...)
+}
+
+// sourceOf prefixes the source reported by backend i, e.g. metamask/high,
+// with its name.
+func (p *FallbackGasPricer) sourceOf(i int, source string) string {
+	if source == "" || source == p.names[i] {
+		return p.names[i]
+	}
+
+	return p.names[i] + ":" + source
+}
+
+// order returns the backends to ask, those cooling down after a failure
+// coming last.
+func (p *FallbackGasPricer) order() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	order := make([]int, 0, len(p.backends))
+	var coolingDown []int
+	for i := range p.backends {
+		if now.Before(p.failedUntil[i]) {
+			coolingDown = append(coolingDown, i)
+			continue
+		}
+		order = append(order, i)
+	}
+
+	return append(order, coolingDown...)
+}
+
+func (p *FallbackGasPricer) setFailed(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failedUntil[i] = time.Now().Add(p.cooldown)
+}
+
+func (p *FallbackGasPricer) setSource(i int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.failedUntil[i] = time.Time{}
+	if p.source != p.names[i] {
+		log.Printf("Use gas price: source=%s", p.names[i])
+		p.source = p.names[i]
+	}
+}
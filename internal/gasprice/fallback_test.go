@@ -0,0 +1,80 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGasPricer struct {
+	calls  int
+	maxFee int64
+	source string
+	err    error
+}
+
+func (p *fakeGasPricer) GasPrice(_ context.Context) (GasQuote, error) {
+	p.calls++
+	if p.err != nil {
+		return GasQuote{}, p.err
+	}
+
+	return GasQuote{MaxFee: big.NewInt(p.maxFee), TipCap: big.NewInt(1e9), Source: p.source}, nil
+}
+
+func TestFallbackGasPricer(t *testing.T) {
+	metamask := &fakeGasPricer{maxFee: 30e9, source: "metamask/high", err: errors.New("service unavailable")}
+	node := &fakeGasPricer{maxFee: 20e9}
+	pricer := NewFallbackGasPricer(
+		[]string{"metamask", "fee_history", "static"},
+		[]GasPricer{metamask, node, NewStaticGasPricer(big.NewInt(10e9), big.NewInt(1e9))},
+		time.Minute,
+	)
+
+	quote, err := pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20e9), quote.MaxFee)
+	require.Equal(t, "fee_history", pricer.Source())
+	require.Equal(t, "fee_history", quote.Source)
+
+	// The failing backend is skipped while cooling down.
+	metamask.err = nil
+	quote, err = pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20e9), quote.MaxFee)
+	require.Equal(t, 1, metamask.calls)
+
+	node.err = errors.New("timeout")
+	quote, err = pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10e9), quote.MaxFee)
+	require.Equal(t, "static", pricer.Source())
+	require.Equal(t, "static", quote.Source)
+
+	// The source of the backend is kept.
+	pricer = NewFallbackGasPricer([]string{"metamask"}, []GasPricer{metamask}, time.Minute)
+	quote, err = pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "metamask:metamask/high", quote.Source)
+}
+
+func TestFallbackGasPricerCoolingDown(t *testing.T) {
+	metamask := &fakeGasPricer{maxFee: 30e9, err: errors.New("service unavailable")}
+	node := &fakeGasPricer{maxFee: 20e9, err: errors.New("timeout")}
+	pricer := NewFallbackGasPricer([]string{"metamask", "fee_history"}, []GasPricer{metamask, node}, time.Minute)
+
+	_, err := pricer.GasPrice(context.Background())
+	require.ErrorContains(t, err, "metamask: service unavailable")
+	require.ErrorContains(t, err, "fee_history: timeout")
+
+	// Backends cooling down are still asked when no other one answers.
+	node.err = nil
+	quote, err := pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20e9), quote.MaxFee)
+	require.Equal(t, 2, metamask.calls)
+}
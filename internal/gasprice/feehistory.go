@@ -0,0 +1,112 @@
+package gasprice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+)
+
+const (
+	sourceFeeHistory = "fee_history"
+
+	feeHistoryBlocks = 10
+
+	defaultRewardPercentile  = 50
+	defaultBaseFeeMultiplier = 2
+)
+
+// FeeHistoryClient is the subset of ethclient.Client used to price gas from
+// the node.
+type FeeHistoryClient interface {
+	FeeHistory(
+		ctx context.Context, blockCount uint64, lastBlock *big.Int, rewardPercentiles []float64,
+	) (*ethereum.FeeHistory, error)
+}
+
+// FeeHistoryGasPricer prices gas from the eth_feeHistory of the node: the tip
+// is the median over the last blocks of the rewards at percentile, and the max
+// fee is the next block base fee times baseFeeMultiplier plus the tip, so the
+// transaction stays valid while the base fee rises.
+type FeeHistoryGasPricer struct {
+	client            FeeHistoryClient
+	percentile        float64
+	baseFeeMultiplier float64
+}
+
+// NewFeeHistoryGasPricer creates a pricer, zero percentile and
+// baseFeeMultiplier defaulting to the median reward and twice the base fee.
+func NewFeeHistoryGasPricer(client FeeHistoryClient, percentile, baseFeeMultiplier float64) *FeeHistoryGasPricer {
+	if percentile == 0 {
+		percentile = defaultRewardPercentile
+	}
+	if baseFeeMultiplier == 0 {
+		baseFeeMultiplier = defaultBaseFeeMultiplier
+	}
+
+	return &FeeHistoryGasPricer{
+		client:            client,
+		percentile:        percentile,
+		baseFeeMultiplier: baseFeeMultiplier,
+	}
+}
+
+func (p *FeeHistoryGasPricer) GasPrice(ctx context.Context) (GasQuote, error) {
+	history, err := p.client.FeeHistory(ctx, feeHistoryBlocks, nil, []float64{p.percentile})
+	if err != nil {
+		return GasQuote{}, fmt.Errorf("get fee history: %w", err)
+	}
+	if len(history.BaseFee) == 0 {
+		return GasQuote{}, errors.New("empty fee history")
+	}
+
+	baseFee := nextBaseFee(history)
+	tipCap := medianReward(history.Reward)
+	maxFee := MulFloat(baseFee, p.baseFeeMultiplier)
+
+	return GasQuote{
+		BaseFee:   baseFee,
+		MaxFee:    maxFee.Add(maxFee, tipCap),
+		TipCap:    tipCap,
+		GasPrice:  new(big.Int).Add(baseFee, tipCap),
+		Source:    sourceFeeHistory,
+		Timestamp: time.Now(),
+	}, nil
+}
+
+// nextBaseFee returns the base fee of the next block, which nodes append to
+// the base fees of the history, or projects it from the gas used by the last
+// block as EIP-1559 does.
+func nextBaseFee(history *ethereum.FeeHistory) *big.Int {
+	baseFee := history.BaseFee[len(history.BaseFee)-1]
+	if len(history.BaseFee) > len(history.GasUsedRatio) {
+		return baseFee
+	}
+
+	// The base fee changes by up to 1/8 as the gas used moves from the target,
+	// half of the gas limit, to zero or to the limit.
+	ratioBPS := int64(history.GasUsedRatio[len(history.GasUsedRatio)-1] * 10_000)
+	delta := new(big.Int).Mul(baseFee, big.NewInt(ratioBPS-5_000))
+	delta.Div(delta, big.NewInt(5_000*8))
+
+	return delta.Add(delta, baseFee)
+}
+
+func medianReward(rewards [][]*big.Int) *big.Int {
+	tips := make([]*big.Int, 0, len(rewards))
+	for _, reward := range rewards {
+		if len(reward) > 0 && reward[0] != nil {
+			tips = append(tips, reward[0])
+		}
+	}
+	if len(tips) == 0 {
+		return new(big.Int)
+	}
+
+	slices.SortFunc(tips, func(a, b *big.Int) int { return a.Cmp(b) })
+	return new(big.Int).Set(tips[len(tips)/2])
+}
@@ -0,0 +1,68 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNode(result string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + result + `}`))
+	}))
+}
+
+func TestFeeHistoryGasPricer(t *testing.T) {
+	// Base fees of 10, 12 and 14 gwei then 16 gwei for the next block, with
+	// tips of 1, 3 and 2 gwei.
+	node := newTestNode(`{
+		"oldestBlock": "0x1",
+		"baseFeePerGas": ["0x2540be400", "0x2cb417800", "0x342770c00", "0x3b9aca000"],
+		"gasUsedRatio": [0.9, 0.9, 0.9],
+		"reward": [["0x3b9aca00"], ["0xb2d05e00"], ["0x77359400"]]
+	}`)
+	defer node.Close()
+
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	quote, err := NewFeeHistoryGasPricer(client, 0, 0).GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(16e9), quote.BaseFee)
+	require.Equal(t, big.NewInt((2*16+2)*1e9), quote.MaxFee)
+	require.Equal(t, big.NewInt(2e9), quote.TipCap)
+	require.Equal(t, big.NewInt(18e9), quote.GasPrice)
+	require.Equal(t, "fee_history", quote.Source)
+
+	quote, err = NewFeeHistoryGasPricer(client, 90, 1.5).GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt((1.5*16+2)*1e9), quote.MaxFee)
+}
+
+func TestFeeHistoryGasPricerProjectsBaseFee(t *testing.T) {
+	// A full block raises the base fee of 16 gwei by 1/8.
+	node := newTestNode(`{
+		"oldestBlock": "0x1",
+		"baseFeePerGas": ["0x3b9aca000"],
+		"gasUsedRatio": [1],
+		"reward": [["0x3b9aca00"]]
+	}`)
+	defer node.Close()
+
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	quote, err := NewFeeHistoryGasPricer(client, 0, 1).GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(18e9), quote.BaseFee)
+	require.Equal(t, big.NewInt((18+1)*1e9), quote.MaxFee)
+	require.Equal(t, big.NewInt(1e9), quote.TipCap)
+}
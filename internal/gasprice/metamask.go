@@ -5,17 +5,88 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
-	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// congestedNetwork is the network congestion above which adaptive
+	// pricers escalate.
+	congestedNetwork = 0.7
+	// escalationPremiumBPS is added to the tip of the high tier when
+	// adaptive pricers escalate past it.
+	escalationPremiumBPS = 2_000
+
+	trendUp = "up"
+
+	sourceMetamask = "metamask"
 )
 
+// Tier is a Metamask gas fee suggestion level.
+type Tier int
+
+const (
+	TierLow Tier = iota
+	TierMedium
+	TierHigh
+)
+
+// ParseTier parses "low", "medium" or "high", the empty tier being high.
+func ParseTier(s string) (Tier, error) {
+	switch s {
+	case "low":
+		return TierLow, nil
+	case "medium":
+		return TierMedium, nil
+	case "", "high":
+		return TierHigh, nil
+	default:
+		return 0, fmt.Errorf("invalid gas tier: %s", s)
+	}
+}
+
+func (t Tier) String() string {
+	switch t {
+	case TierLow:
+		return "low"
+	case TierMedium:
+		return "medium"
+	default:
+		return "high"
+	}
+}
+
+// MetamaskGasPricer prices gas with the suggestion of its tier. Adaptive
+// pricers escalate to the next tier, or add a premium to the high tier, while
+// the network is congested or priority fees are rising.
 type MetamaskGasPricer struct {
-	client  *http.Client
-	baseURL *url.URL
+	client   *http.Client
+	baseURL  *url.URL
+	tier     Tier
+	adaptive bool
+}
+
+type MetamaskOption func(*MetamaskGasPricer)
+
+// WithTier replaces the default high tier.
+func WithTier(tier Tier) MetamaskOption {
+	return func(p *MetamaskGasPricer) {
+		p.tier = tier
+	}
+}
+
+// WithAdaptiveTier escalates the tier when fees are rising.
+func WithAdaptiveTier() MetamaskOption {
+	return func(p *MetamaskGasPricer) {
+		p.adaptive = true
+	}
 }
 
-func NewMetamaskGasPricer(baseURL string, httpClient *http.Client) (*MetamaskGasPricer, error) {
+func NewMetamaskGasPricer(baseURL string, httpClient *http.Client, opts ...MetamaskOption) (*MetamaskGasPricer, error) {
 	u, err := url.Parse(baseURL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid url: %w", err)
@@ -25,45 +96,90 @@ func NewMetamaskGasPricer(baseURL string, httpClient *http.Client) (*MetamaskGas
 		httpClient = http.DefaultClient
 	}
 
-	return &MetamaskGasPricer{
+	p := &MetamaskGasPricer{
 		client:  httpClient,
 		baseURL: u,
-	}, nil
+		tier:    TierHigh,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p, nil
 }
 
-func (p *MetamaskGasPricer) GasPrice(ctx context.Context) (float64, float64, error) {
+func (p *MetamaskGasPricer) GasPrice(ctx context.Context) (GasQuote, error) {
 	u := p.baseURL.JoinPath("suggestedGasFees")
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	if err != nil {
-		return 0, 0, fmt.Errorf("new request: %w", err)
+		return GasQuote{}, fmt.Errorf("new request: %w", err)
 	}
 
 	resp, err := p.client.Do(req)
 	if err != nil {
-		return 0, 0, fmt.Errorf("do request: %w", err)
+		return GasQuote{}, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return 0, 0, fmt.Errorf("%w: %s", errors.New("request failed"), resp.Status)
+		return GasQuote{}, fmt.Errorf("%w: %s", errors.New("request failed"), resp.Status)
 	}
 
 	var res SuggestedGasFeesResp
 	if err = json.NewDecoder(resp.Body).Decode(&res); err != nil {
-		return 0, 0, fmt.Errorf("decode response: %w", err)
+		return GasQuote{}, fmt.Errorf("decode response: %w", err)
+	}
+
+	tier, premium := p.tier, false
+	if p.adaptive && res.feesRising() {
+		if tier < TierHigh {
+			tier++
+		} else {
+			premium = true
+		}
 	}
+	fee := res.fee(tier)
 
-	gasPriceGwei, err := strconv.ParseFloat(res.High.SuggestedMaxFeePerGas, 64)
+	maxFee, err := gweiToWei(fee.SuggestedMaxFeePerGas)
 	if err != nil {
-		return 0, 0, fmt.Errorf("decode gas price: %w", err)
+		return GasQuote{}, fmt.Errorf("decode gas price: %w", err)
 	}
 
-	tipCapGwei, err := strconv.ParseFloat(res.High.SuggestedMaxPriorityFeePerGas, 64)
+	tipCap, err := gweiToWei(fee.SuggestedMaxPriorityFeePerGas)
 	if err != nil {
-		return 0, 0, fmt.Errorf("decode max priority gas: %w", err)
+		return GasQuote{}, fmt.Errorf("decode max priority gas: %w", err)
+	}
+
+	if premium {
+		extraTip := new(big.Int).Mul(tipCap, big.NewInt(escalationPremiumBPS))
+		extraTip.Div(extraTip, big.NewInt(10_000))
+		maxFee.Add(maxFee, extraTip)
+		tipCap.Add(tipCap, extraTip)
+	}
+
+	quote := GasQuote{
+		MaxFee:    maxFee,
+		TipCap:    tipCap,
+		Source:    sourceMetamask + "/" + tier.String(),
+		Timestamp: time.Now(),
+	}
+	if baseFee, err := gweiToWei(res.EstimatedBaseFee); err == nil {
+		quote.BaseFee = baseFee
+		quote.GasPrice = new(big.Int).Add(baseFee, tipCap)
+	}
+
+	return quote, nil
+}
+
+// gweiToWei parses a decimal amount of gwei without losing precision.
+func gweiToWei(gwei string) (*big.Int, error) {
+	amount, ok := new(big.Rat).SetString(gwei)
+	if !ok {
+		return nil, fmt.Errorf("invalid gwei amount: %q", gwei)
 	}
 
-	return gasPriceGwei, tipCapGwei, nil
+	amount.Mul(amount, new(big.Rat).SetInt64(params.GWei))
+	return new(big.Int).Quo(amount.Num(), amount.Denom()), nil
 }
 
 type GasFee struct {
@@ -85,3 +201,18 @@ type SuggestedGasFeesResp struct {
 	PriorityFeeTrend           string   `json:"priorityFeeTrend"`
 	BaseFeeTrend               string   `json:"baseFeeTrend"`
 }
+
+func (r SuggestedGasFeesResp) fee(tier Tier) GasFee {
+	switch tier {
+	case TierLow:
+		return r.Low
+	case TierMedium:
+		return r.Medium
+	default:
+		return r.High
+	}
+}
+
+func (r SuggestedGasFeesResp) feesRising() bool {
+	return r.NetworkCongestion >= congestedNetwork || r.PriorityFeeTrend == trendUp
+}
@@ -0,0 +1,90 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetamask(networkCongestion, priorityFeeTrend string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/suggestedGasFees" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"low": {"suggestedMaxPriorityFeePerGas": "1", "suggestedMaxFeePerGas": "20"},
+			"medium": {"suggestedMaxPriorityFeePerGas": "1.5", "suggestedMaxFeePerGas": "25"},
+			"high": {"suggestedMaxPriorityFeePerGas": "2", "suggestedMaxFeePerGas": "30"},
+			"estimatedBaseFee": "18.123456789",
+			"networkCongestion": ` + networkCongestion + `,
+			"priorityFeeTrend": "` + priorityFeeTrend + `"
+		}`))
+	}))
+}
+
+func TestMetamaskGasPricerTier(t *testing.T) {
+	server := newTestMetamask("0.3", "down")
+	defer server.Close()
+
+	pricer, err := NewMetamaskGasPricer(server.URL, nil)
+	require.NoError(t, err)
+	quote, err := pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(30e9), quote.MaxFee)
+	require.Equal(t, big.NewInt(2e9), quote.TipCap)
+	require.Equal(t, big.NewInt(18_123456789), quote.BaseFee)
+	require.Equal(t, big.NewInt(20_123456789), quote.GasPrice)
+	require.Equal(t, "metamask/high", quote.Source)
+
+	pricer, err = NewMetamaskGasPricer(server.URL, nil, WithTier(TierLow), WithAdaptiveTier())
+	require.NoError(t, err)
+	quote, err = pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20e9), quote.MaxFee)
+	require.Equal(t, big.NewInt(1e9), quote.TipCap)
+}
+
+func TestMetamaskGasPricerAdaptive(t *testing.T) {
+	server := newTestMetamask("0.3", "up")
+	defer server.Close()
+
+	// Rising priority fees escalate to the next tier.
+	pricer, err := NewMetamaskGasPricer(server.URL, nil, WithTier(TierMedium), WithAdaptiveTier())
+	require.NoError(t, err)
+	quote, err := pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(30e9), quote.MaxFee)
+	require.Equal(t, big.NewInt(2e9), quote.TipCap)
+	require.Equal(t, "metamask/high", quote.Source)
+
+	congested := newTestMetamask("0.9", "level")
+	defer congested.Close()
+
+	// Past the high tier, a premium is added to the tip.
+	pricer, err = NewMetamaskGasPricer(congested.URL, nil, WithAdaptiveTier())
+	require.NoError(t, err)
+	quote, err = pricer.GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(30_400_000_000), quote.MaxFee)
+	require.Equal(t, big.NewInt(2_400_000_000), quote.TipCap)
+}
+
+func TestParseTier(t *testing.T) {
+	tier, err := ParseTier("")
+	require.NoError(t, err)
+	require.Equal(t, TierHigh, tier)
+
+	tier, err = ParseTier("medium")
+	require.NoError(t, err)
+	require.Equal(t, TierMedium, tier)
+
+	_, err = ParseTier("urgent")
+	require.Error(t, err)
+}
@@ -0,0 +1,42 @@
+package gasprice
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+const sourceNode = "eth_gas_price"
+
+// GasPriceClient is the subset of ethclient.Client used to price legacy
+// transactions.
+type GasPriceClient interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// NodeGasPricer prices gas with the eth_gasPrice of the node, for chains
+// without EIP-1559 where the legacy gas price is both the max fee and the
+// tip.
+type NodeGasPricer struct {
+	client GasPriceClient
+}
+
+func NewNodeGasPricer(client GasPriceClient) *NodeGasPricer {
+	return &NodeGasPricer{client: client}
+}
+
+func (p *NodeGasPricer) GasPrice(ctx context.Context) (GasQuote, error) {
+	gasPrice, err := p.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return GasQuote{}, fmt.Errorf("suggest gas price: %w", err)
+	}
+
+	return GasQuote{
+		MaxFee:    new(big.Int).Set(gasPrice),
+		TipCap:    new(big.Int).Set(gasPrice),
+		GasPrice:  gasPrice,
+		Source:    sourceNode,
+		Timestamp: time.Now(),
+	}, nil
+}
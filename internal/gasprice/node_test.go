@@ -0,0 +1,25 @@
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeGasPricer(t *testing.T) {
+	node := newTestNode(`"0xb2d05e00"`)
+	defer node.Close()
+
+	client, err := ethclient.Dial(node.URL)
+	require.NoError(t, err)
+	defer client.Close()
+
+	quote, err := NewNodeGasPricer(client).GasPrice(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(3e9), quote.GasPrice)
+	require.Equal(t, big.NewInt(3e9), quote.MaxFee)
+	require.Equal(t, "eth_gas_price", quote.Source)
+}
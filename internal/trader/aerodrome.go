@@ -0,0 +1,165 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+// AerodromeRoutes is the hops an Aerodrome swap goes through. Empty routes
+// swap straight from the input token to the output token through their
+// volatile pool.
+type AerodromeRoutes []blockchain.AerodromeRoute
+
+// ParseAerodromeRoutes parses routes whose hops must be chained, each one
+// starting with the token the previous one ends with.
+func ParseAerodromeRoutes(hops []config.AerodromeRoute) (AerodromeRoutes, error) {
+	routes := make(AerodromeRoutes, 0, len(hops))
+	for i, hop := range hops {
+		if !common.IsHexAddress(hop.From) || !common.IsHexAddress(hop.To) {
+			return nil, fmt.Errorf("invalid route %d tokens: %s, %s", i, hop.From, hop.To)
+		}
+		if hop.Factory != "" && !common.IsHexAddress(hop.Factory) {
+			return nil, fmt.Errorf("invalid route %d factory: %s", i, hop.Factory)
+		}
+
+		route := blockchain.AerodromeRoute{
+			From:    common.HexToAddress(hop.From),
+			To:      common.HexToAddress(hop.To),
+			Stable:  hop.Stable,
+			Factory: common.HexToAddress(hop.Factory),
+		}
+		if i > 0 && routes[i-1].To != route.From {
+			return nil, fmt.Errorf("route %d does not start with %v", i, routes[i-1].To)
+		}
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
+
+// routes returns the routes from the input token to the output token, ETH
+// being swapped as WETH.
+func (r AerodromeRoutes) routes(inputToken, outputToken, weth common.Address) ([]blockchain.AerodromeRoute, error) {
+	inputToken, outputToken = toTokenAddress(inputToken, weth), toTokenAddress(outputToken, weth)
+	if len(r) == 0 {
+		return []blockchain.AerodromeRoute{{From: inputToken, To: outputToken}}, nil
+	}
+
+	if r[0].From != inputToken || r[len(r)-1].To != outputToken {
+		return nil, fmt.Errorf("routes do not swap %v to %v", inputToken, outputToken)
+	}
+
+	return r, nil
+}
+
+// AerodromeBuilder builds swaps for Aerodrome and other Solidly style routers,
+// which only support exact input swaps.
+type AerodromeBuilder struct {
+	router        common.Address
+	weth          common.Address
+	routes        AerodromeRoutes
+	feeOnTransfer bool
+}
+
+func NewAerodromeBuilder(router, weth common.Address, routes AerodromeRoutes, feeOnTransfer bool) *AerodromeBuilder {
+	return &AerodromeBuilder{
+		router:        router,
+		weth:          weth,
+		routes:        routes,
+		feeOnTransfer: feeOnTransfer,
+	}
+}
+
+func (b *AerodromeBuilder) BuildSwap(_ context.Context, params SwapParams) (Call, error) {
+	if params.ExactOutput {
+		return Call{}, errors.New("aerodrome does not support exact output")
+	}
+
+	routes, err := b.routes.routes(params.InputToken, params.OutputToken, b.weth)
+	if err != nil {
+		return Call{}, err
+	}
+
+	deadline := params.Deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(defaultSwapDeadline)
+	}
+
+	var data []byte
+	ethIn, ethOut := isEth(params.InputToken), isEth(params.OutputToken)
+	switch {
+	case ethIn:
+		data, err = blockchain.EncodeAerodromeSwapExactETHForTokens(
+			params.MinAmountOut, routes, params.Recipient, deadline.Unix(), b.feeOnTransfer)
+	case ethOut:
+		data, err = blockchain.EncodeAerodromeSwapExactTokensForETH(
+			params.AmountIn, params.MinAmountOut, routes, params.Recipient, deadline.Unix(), b.feeOnTransfer)
+	default:
+		data, err = blockchain.EncodeAerodromeSwapExactTokensForTokens(
+			params.AmountIn, params.MinAmountOut, routes, params.Recipient, deadline.Unix(), b.feeOnTransfer)
+	}
+	if err != nil {
+		return Call{}, err
+	}
+
+	call := Call{To: b.router, Data: data}
+	if ethIn {
+		call.Value = params.AmountIn
+	}
+
+	return call, nil
+}
+
+// DecodeAmountOut decodes the last amount returned by the swap. Fee on
+// transfer swaps return nothing, so their amount out is unknown.
+func (b *AerodromeBuilder) DecodeAmountOut(data []byte) (*big.Int, error) {
+	if b.feeOnTransfer {
+		return nil, nil
+	}
+
+	return lastAmount(data)
+}
+
+// AerodromeQuoter quotes swaps with the getAmountsOut of an Aerodrome router.
+type AerodromeQuoter struct {
+	caller ContractCaller
+	router common.Address
+	weth   common.Address
+	routes AerodromeRoutes
+}
+
+func NewAerodromeQuoter(caller ContractCaller, router, weth common.Address, routes AerodromeRoutes) *AerodromeQuoter {
+	return &AerodromeQuoter{
+		caller: caller,
+		router: router,
+		weth:   weth,
+		routes: routes,
+	}
+}
+
+func (q *AerodromeQuoter) QuoteAmountOut(ctx context.Context, params SwapParams) (*big.Int, error) {
+	routes, err := q.routes.routes(params.InputToken, params.OutputToken, q.weth)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := blockchain.EncodeAerodromeGetAmountsOut(params.AmountIn, routes)
+	if err != nil {
+		return nil, fmt.Errorf("encode quote: %w", err)
+	}
+
+	res, err := q.caller.CallContract(ctx, callMsg(q.router, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("call quote: %w", err)
+	}
+
+	return lastAmount(res)
+}
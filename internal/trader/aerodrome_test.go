@@ -0,0 +1,80 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+func TestParseAerodromeRoutes(t *testing.T) {
+	routes, err := ParseAerodromeRoutes([]config.AerodromeRoute{
+		{From: "0x4200000000000000000000000000000000000006", To: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"},
+		{
+			From:    "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913",
+			To:      "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb",
+			Stable:  true,
+			Factory: "0x420DD381b31aEf6683db6B902084cB0FFECe40Da",
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, routes, 2)
+	require.Equal(t, common.Address{}, routes[0].Factory)
+	require.True(t, routes[1].Stable)
+
+	_, err = ParseAerodromeRoutes([]config.AerodromeRoute{
+		{From: "0x4200000000000000000000000000000000000006", To: "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"},
+		{From: "0x4200000000000000000000000000000000000006", To: "0x50c5725949A6F0c72E6C4a641F24049A917DB0Cb"},
+	})
+	require.Error(t, err)
+}
+
+func TestAerodromeBuilder(t *testing.T) {
+	router := common.HexToAddress("0xcF77a3Ba9A5CA399B7c97c74d54e5b1Beb874E43")
+	weth := common.HexToAddress("0x4200000000000000000000000000000000000006")
+	usdc := common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913")
+	builder := NewAerodromeBuilder(router, weth, nil, false)
+
+	params := SwapParams{
+		Recipient:    common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"),
+		InputToken:   ethAddress,
+		OutputToken:  usdc,
+		AmountIn:     big.NewInt(1e18),
+		MinAmountOut: big.NewInt(2500_000000),
+	}
+	call, err := builder.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, router, call.To)
+	require.Equal(t, big.NewInt(1e18), call.Value)
+	require.Equal(t, "0x903638a4", hexutil.Encode(call.Data[:4]))
+
+	params.ExactOutput = true
+	_, err = builder.BuildSwap(context.Background(), params)
+	require.Error(t, err)
+}
+
+func TestAerodromeQuoter(t *testing.T) {
+	var result []byte
+	for _, v := range []int64{32, 2, 1e18, 2500_000000} {
+		result = append(result, int256Word(v)...)
+	}
+	caller := &fakeContractCaller{result: result}
+	router := common.HexToAddress("0xcF77a3Ba9A5CA399B7c97c74d54e5b1Beb874E43")
+
+	quoter := NewAerodromeQuoter(caller, router, common.HexToAddress("0x4200000000000000000000000000000000000006"), nil)
+	amountOut, err := quoter.QuoteAmountOut(context.Background(), SwapParams{
+		InputToken:  ethAddress,
+		OutputToken: common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+		AmountIn:    big.NewInt(1e18),
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2500_000000), amountOut)
+	require.Len(t, caller.msgs, 1)
+	require.Equal(t, router, *caller.msgs[0].To)
+	require.Equal(t, "0x5509a1ac", hexutil.Encode(caller.msgs[0].Data[:4]))
+}
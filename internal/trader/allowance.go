@@ -0,0 +1,162 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+const (
+	approveExact     = "exact"
+	approveUnlimited = "unlimited"
+)
+
+var errInsufficientAllowance = errors.New("insufficient allowance")
+
+// spender returns the address allowed to spend the input token.
+func (t *Trader) spender() common.Address {
+	switch {
+	case t.cfg.ApproveSpender != "":
+		return common.HexToAddress(t.cfg.ApproveSpender)
+	case t.cfg.Permit2Address != "":
+		return common.HexToAddress(t.cfg.Permit2Address)
+	}
+
+	return common.HexToAddress(t.cfg.RouterAddress)
+}
+
+// ensureAllowance makes sure the spender can spend the input amount of an
+// ERC20 input token, sending an approve transaction when approve is enabled.
+// It returns the lowest nonce the swap may use, which is zero when no
+// approve is sent.
+func (t *Trader) ensureAllowance(ctx context.Context, account config.Account) (uint64, error) {
+	token := common.HexToAddress(t.cfg.InputToken)
+	if isEth(token) {
+		return 0, nil
+	}
+
+	accountAddress, err := t.signer.Address(account)
+	if err != nil {
+		return 0, err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, tradeTimeout)
+	defer cancel()
+
+	allowance, err := t.allowance(callCtx, token, accountAddress)
+	if err != nil {
+		log.Printf("Fail to get allowance: owner=%v token=%v error=%v", accountAddress, token, err)
+		return 0, err
+	}
+	required := maxInputAmount(account)
+	if required == nil {
+		return 0, errors.New("max_input_amount is required for exact output")
+	}
+	if allowance.Cmp(required) >= 0 {
+		return 0, nil
+	}
+
+	var amount *big.Int
+	switch t.cfg.Approve {
+	case approveExact:
+		amount = required
+	case approveUnlimited:
+		amount = math.MaxBig256
+	case "":
+		return 0, fmt.Errorf("%w: allowance=%v amount=%v", errInsufficientAllowance, allowance, required)
+	default:
+		return 0, fmt.Errorf("invalid approve: %s", t.cfg.Approve)
+	}
+
+	tx, err := t.approve(ctx, account, accountAddress, token, amount)
+	if err != nil {
+		log.Printf("Fail to approve: owner=%v token=%v error=%v", accountAddress, token, err)
+		return 0, err
+	}
+
+	return tx.Nonce() + 1, nil
+}
+
+func (t *Trader) allowance(ctx context.Context, token, owner common.Address) (*big.Int, error) {
+	data, err := blockchain.EncodeAllowance(owner, t.spender())
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := t.client.CallContract(ctx, callMsg(token, data), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return blockchain.DecodeAllowance(res)
+}
+
+// approve sends an approve transaction and waits for it to be mined unless
+// checking the transaction status is skipped. Only sending is bound by the
+// trade timeout, as waiting may span several replacements.
+func (t *Trader) approve(
+	ctx context.Context, account config.Account, accountAddress, token common.Address, amount *big.Int,
+) (*types.Transaction, error) {
+	data, err := blockchain.EncodeApprove(t.spender(), amount)
+	if err != nil {
+		return nil, err
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, tradeTimeout)
+	defer cancel()
+
+	nonce, err := t.client.PendingNonceAt(sendCtx, accountAddress)
+	if err != nil {
+		return nil, fmt.Errorf("get nonce: %w", err)
+	}
+
+	msg := callMsg(token, data)
+	msg.From = accountAddress
+	gasLimit, err := t.client.EstimateGas(sendCtx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("estimate gas: %w", err)
+	}
+	gasLimit = gasLimit * gasMultiplierBPS / 10_000
+
+	gasQuote, err := t.gasPricerOf(account).GasPrice(sendCtx)
+	if err != nil {
+		return nil, fmt.Errorf("get gas price: %w", err)
+	}
+	// Approvals are not urgent, max_gas_fee only caps the quoted fees.
+	maxGasPrice, gasTipCap := t.cappedGasFees(gasLimit, gasQuote, account.MaxGasFee)
+
+	signedTx, err := t.signer.SignTx(account, t.newTx(nonce, maxGasPrice, gasTipCap, gasLimit, &token, data, nil))
+	if err != nil {
+		return nil, fmt.Errorf("sign approve: %w", err)
+	}
+
+	if err = t.broadcaster.SendTransactions(sendCtx, []*types.Transaction{signedTx})[0].Err; err != nil {
+		return nil, fmt.Errorf("send approve: %w", err)
+	}
+
+	log.Printf("Send approve: owner=%v token=%v spender=%v amount=%v transactionHash=%v",
+		accountAddress, token, t.spender(), amount, signedTx.Hash())
+
+	if t.cfg.SkipCheckTxStatus {
+		return signedTx, nil
+	}
+
+	res := Result{Account: account, Address: accountAddress, TxHashes: []common.Hash{signedTx.Hash()}}
+	if err = t.waitMined(ctx, &res, signedTx); err != nil {
+		return nil, fmt.Errorf("wait approve: %w", err)
+	}
+	if res.Receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("approve failed: transactionHash=%v", res.TxHash)
+	}
+
+	return signedTx, nil
+}
@@ -0,0 +1,85 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+func TestTraderRunApprove(t *testing.T) {
+	cfg := testConfig()
+	cfg.InputToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	cfg.OutputToken = "0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"
+	cfg.Approve = approveUnlimited
+	client := newFakeChainClient()
+	client.callResult = int256Word(0)
+
+	_, err := newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 2)
+
+	approveTx, swapTx := client.sent[0], client.sent[1]
+	wantData, err := blockchain.EncodeApprove(common.HexToAddress(cfg.RouterAddress), math.MaxBig256)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress(cfg.InputToken), *approveTx.To())
+	require.Equal(t, wantData, approveTx.Data())
+	require.Equal(t, uint64(0), approveTx.Nonce())
+	require.Equal(t, uint64(1), swapTx.Nonce())
+	require.Equal(t, common.HexToAddress(cfg.RouterAddress), *swapTx.To())
+	require.Zero(t, swapTx.Value().Sign())
+}
+
+func TestTraderRunApproveMaxGasFee(t *testing.T) {
+	cfg := testConfig()
+	cfg.InputToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	cfg.OutputToken = "0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"
+	cfg.Approve = approveExact
+	cfg.Accounts[0].MaxGasFee = big.NewInt(1e15)
+	client := newFakeChainClient()
+	client.callResult = int256Word(0)
+
+	_, err := newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 2)
+
+	// The approve pays the quoted tip, its fee cap lowered to max_gas_fee.
+	approveTx := client.sent[0]
+	require.Equal(t, big.NewInt(2e9), approveTx.GasTipCap())
+	require.Equal(t, new(big.Int).Div(big.NewInt(1e15), new(big.Int).SetUint64(approveTx.Gas())), approveTx.GasFeeCap())
+
+	// Within max_gas_fee, the approve is priced at the quote.
+	cfg.Accounts[0].MaxGasFee = big.NewInt(1e18)
+	client = newFakeChainClient()
+	client.callResult = int256Word(0)
+
+	_, err = newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(20e9), client.sent[0].GasFeeCap())
+	require.Equal(t, big.NewInt(2e9), client.sent[0].GasTipCap())
+}
+
+func TestTraderRunInsufficientAllowance(t *testing.T) {
+	cfg := testConfig()
+	cfg.InputToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	cfg.OutputToken = "0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"
+	client := newFakeChainClient()
+	client.callResult = int256Word(0)
+
+	results, err := newTestTrader(cfg, client).Run(context.Background())
+	require.ErrorIs(t, err, errInsufficientAllowance)
+	require.Empty(t, client.sent)
+	require.Equal(t, common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"), results[0].Address)
+
+	// Enough allowance, nothing to approve.
+	client.callResult = int256Word(2e18)
+	_, err = newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 1)
+	require.Equal(t, uint64(0), client.sent[0].Nonce())
+}
@@ -0,0 +1,139 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/hiepnv90/ilo/internal/clients/flashbots"
+)
+
+const defaultBundleBlocks = 3
+
+// Broadcaster sends signed transactions to the network and returns one result
+// per transaction, in the same order.
+type Broadcaster interface {
+	SendTransactions(ctx context.Context, txs []*types.Transaction) []SendResult
+}
+
+// SendResult is the outcome of sending one transaction. SentAt is when the
+// transaction left for the network and is zero if it was never sent.
+type SendResult struct {
+	SentAt time.Time
+	Err    error
+}
+
+// TransactionSender is implemented by ethclient.Client.
+type TransactionSender interface {
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// ClientBroadcaster sends every transaction through the node concurrently.
+type ClientBroadcaster struct {
+	sender TransactionSender
+}
+
+func NewClientBroadcaster(sender TransactionSender) *ClientBroadcaster {
+	return &ClientBroadcaster{sender: sender}
+}
+
+func (b *ClientBroadcaster) SendTransactions(ctx context.Context, txs []*types.Transaction) []SendResult {
+	results := make([]SendResult, len(txs))
+	var wg sync.WaitGroup
+	for i, tx := range txs {
+		wg.Add(1)
+		go func(i int, tx *types.Transaction) {
+			defer wg.Done()
+			results[i].SentAt = time.Now()
+			results[i].Err = b.sender.SendTransaction(ctx, tx)
+		}(i, tx)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// BundleClient is implemented by flashbots.Client.
+type BundleClient interface {
+	SendBundle(ctx context.Context, txs []*types.Transaction, blockNumber uint64) (flashbots.SendBundleResponse, error)
+	CallBundle(ctx context.Context, txs []*types.Transaction, blockNumber uint64) (flashbots.CallBundleResponse, error)
+}
+
+// BundleBroadcaster sends all transactions as one bundle for each of the next
+// blocks, so they land atomically or not at all. The bundle is simulated first
+// unless simulation is disabled, and nothing is sent if any transaction fails.
+type BundleBroadcaster struct {
+	relay    BundleClient
+	client   ChainClient
+	blocks   uint64
+	simulate bool
+}
+
+func NewBundleBroadcaster(relay BundleClient, client ChainClient, blocks uint64, simulate bool) *BundleBroadcaster {
+	if blocks == 0 {
+		blocks = defaultBundleBlocks
+	}
+
+	return &BundleBroadcaster{
+		relay:    relay,
+		client:   client,
+		blocks:   blocks,
+		simulate: simulate,
+	}
+}
+
+func (b *BundleBroadcaster) SendTransactions(ctx context.Context, txs []*types.Transaction) []SendResult {
+	sentAt, err := b.sendBundle(ctx, txs)
+	results := make([]SendResult, len(txs))
+	for i := range results {
+		results[i] = SendResult{SentAt: sentAt, Err: err}
+	}
+
+	return results
+}
+
+// sendBundle returns when the bundle was sent, which is zero if it failed
+// before reaching the relay.
+func (b *BundleBroadcaster) sendBundle(ctx context.Context, txs []*types.Transaction) (time.Time, error) {
+	blockNumber, err := b.client.BlockNumber(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get block number: %w", err)
+	}
+	targetBlock := blockNumber + 1
+
+	if b.simulate {
+		res, err := b.relay.CallBundle(ctx, txs, targetBlock)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("call bundle: %w", err)
+		}
+
+		for _, r := range res.Results {
+			if r.Error != "" || r.Revert != "" {
+				return time.Time{}, fmt.Errorf("bundle simulation failed: transactionHash=%v error=%s revert=%s",
+					r.TxHash, r.Error, r.Revert)
+			}
+		}
+	}
+
+	sentAt := time.Now()
+	g, gctx := errgroup.WithContext(ctx)
+	for i := uint64(0); i < b.blocks; i++ {
+		block := targetBlock + i
+		g.Go(func() error {
+			res, err := b.relay.SendBundle(gctx, txs, block)
+			if err != nil {
+				return fmt.Errorf("send bundle: blockNumber=%d error=%w", block, err)
+			}
+
+			log.Printf("Submit bundle: bundleHash=%v blockNumber=%d", res.BundleHash, block)
+			return nil
+		})
+	}
+
+	return sentAt, g.Wait()
+}
@@ -0,0 +1,106 @@
+package trader
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/clients/flashbots"
+)
+
+type fakeRelay struct {
+	mu           sync.Mutex
+	callResult   string
+	bundleBlocks []uint64
+}
+
+func (r *fakeRelay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body struct {
+		Method string                       `json:"method"`
+		Params []flashbots.SendBundleParams `json:"params"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch body.Method {
+	case "eth_callBundle":
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":` + r.callResult + `}`))
+	case "eth_sendBundle":
+		r.bundleBlocks = append(r.bundleBlocks, uint64(body.Params[0].BlockNumber))
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"bundleHash":"0x0000000000000000000000000000000000000000000000000000000000000001"}}`))
+	}
+}
+
+type failingSender struct {
+	failing common.Hash
+}
+
+func (s failingSender) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	if tx.Hash() == s.failing {
+		return errors.New("nonce too low")
+	}
+
+	return nil
+}
+
+func TestClientBroadcaster(t *testing.T) {
+	txs := []*types.Transaction{
+		types.NewTx(&types.DynamicFeeTx{Nonce: 1}),
+		types.NewTx(&types.DynamicFeeTx{Nonce: 2}),
+	}
+
+	start := time.Now()
+	results := NewClientBroadcaster(failingSender{failing: txs[1].Hash()}).SendTransactions(context.Background(), txs)
+	require.Len(t, results, 2)
+	require.NoError(t, results[0].Err)
+	require.EqualError(t, results[1].Err, "nonce too low")
+	for _, res := range results {
+		require.False(t, res.SentAt.Before(start))
+		require.False(t, res.SentAt.After(time.Now()))
+	}
+}
+
+func TestBundleBroadcaster(t *testing.T) {
+	relay := &fakeRelay{callResult: `{"results":[{"gasUsed":100000}]}`}
+	srv := httptest.NewServer(relay)
+	defer srv.Close()
+
+	authKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	relayClient, err := flashbots.NewClient(srv.URL, authKey, nil)
+	require.NoError(t, err)
+
+	cfg := testConfig()
+	cfg.SkipCheckTxStatus = true
+	client := newFakeChainClient()
+	client.blockNumber = 99
+
+	trader := newTestTrader(cfg, client)
+	trader.broadcaster = NewBundleBroadcaster(relayClient, client, 2, true)
+
+	results, err := trader.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Empty(t, client.sent)
+	require.ElementsMatch(t, []uint64{101, 102}, relay.bundleBlocks)
+
+	relay.bundleBlocks = nil
+	relay.callResult = `{"results":[{"gasUsed":100000,"revert":"Too little received"}]}`
+	_, err = trader.Run(context.Background())
+	require.ErrorContains(t, err, "Too little received")
+	require.Empty(t, relay.bundleBlocks)
+}
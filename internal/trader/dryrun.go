@@ -0,0 +1,97 @@
+package trader
+
+import (
+	"context"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+// PendingCaller is implemented by ethclient.Client.
+type PendingCaller interface {
+	PendingCallContract(ctx context.Context, msg ethereum.CallMsg) ([]byte, error)
+}
+
+// OutputDecoder is implemented by calldata builders that can decode the
+// amount out returned by their swap call.
+type OutputDecoder interface {
+	DecodeAmountOut(data []byte) (*big.Int, error)
+}
+
+// WithDryRun makes Run prepare and sign the transactions as usual, then
+// simulate them with eth_call against the pending block instead of sending
+// them. The trigger is not waited for.
+func WithDryRun(caller PendingCaller) Option {
+	return func(t *Trader) {
+		t.caller = caller
+	}
+}
+
+func (t *Trader) dryRun(ctx context.Context) ([]Result, error) {
+	results := make([]Result, len(t.cfg.Accounts))
+	t.forEachAccount(func(i int, acc config.Account) {
+		var tx *types.Transaction
+		results[i].Account = acc
+		results[i].Deadline = t.swapDeadline(acc)
+		results[i].Address, tx, results[i].Err = t.prepare(ctx, acc, 0, results[i].Deadline)
+		if results[i].Err != nil {
+			return
+		}
+
+		results[i].TxHash = tx.Hash()
+		results[i].AmountOut, results[i].Err = t.simulate(ctx, results[i].Address, tx, !acc.ExactOutput)
+		if results[i].Err == nil && acc.ExactOutput {
+			results[i].AmountOut = acc.InputAmount
+		}
+	})
+
+	return results, joinResultErrors(results)
+}
+
+// simulate calls tx and, when decodeOutput is set, returns the amount out
+// decoded from the output of the call.
+func (t *Trader) simulate(
+	ctx context.Context, from common.Address, tx *types.Transaction, decodeOutput bool,
+) (*big.Int, error) {
+	ctx, cancel := context.WithTimeout(ctx, tradeTimeout)
+	defer cancel()
+
+	msg := ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	if tx.Type() == types.LegacyTxType {
+		msg.GasPrice = tx.GasPrice()
+	} else {
+		msg.GasFeeCap, msg.GasTipCap = tx.GasFeeCap(), tx.GasTipCap()
+	}
+
+	data, err := t.caller.PendingCallContract(ctx, msg)
+	if err != nil {
+		log.Printf("Fail to simulate transaction: sender=%v error=%v", from, err)
+		return nil, err
+	}
+
+	decoder, ok := t.builder.(OutputDecoder)
+	if !ok || !decodeOutput {
+		log.Printf("Simulate transaction: sender=%v transactionHash=%v", from, tx.Hash())
+		return nil, nil
+	}
+
+	amountOut, err := decoder.DecodeAmountOut(data)
+	if err != nil {
+		log.Printf("Fail to decode simulation output: sender=%v error=%v", from, err)
+		return nil, err
+	}
+
+	log.Printf("Simulate transaction: sender=%v transactionHash=%v amountOut=%v", from, tx.Hash(), amountOut)
+	return amountOut, nil
+}
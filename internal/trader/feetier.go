@@ -0,0 +1,125 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+// StandardFeeTiers are the fee tiers enabled on every Uniswap V3 factory.
+var StandardFeeTiers = []int64{100, 500, 3000, 10000} //nolint:gochecknoglobals
+
+var errNoPool = errors.New("no pool with liquidity")
+
+// FeeTierFinder finds the fee tier of the Uniswap V3 pool a direct swap
+// should go through, so the tier of a new pool is not guessed.
+type FeeTierFinder struct {
+	caller  ContractCaller
+	factory common.Address
+	weth    common.Address
+	quoter  common.Address
+}
+
+// NewFeeTierFinder creates a finder comparing pools by liquidity or, when
+// quoter is not the zero address, by their QuoterV2 quotes.
+func NewFeeTierFinder(caller ContractCaller, factory, weth, quoter common.Address) *FeeTierFinder {
+	return &FeeTierFinder{
+		caller:  caller,
+		factory: factory,
+		weth:    weth,
+		quoter:  quoter,
+	}
+}
+
+// Find returns the fee tier among the standard tiers and extraFees whose pool
+// quotes the most output for params or, without quoter or amount in, has the
+// most liquidity. Pools without liquidity are skipped.
+func (f *FeeTierFinder) Find(ctx context.Context, params SwapParams, extraFees []int64) (int64, error) {
+	tokenA := toTokenAddress(params.InputToken, f.weth)
+	tokenB := toTokenAddress(params.OutputToken, f.weth)
+	byQuote := f.quoter != (common.Address{}) && params.AmountIn != nil
+
+	var bestFee int64
+	var best *big.Int
+	for _, fee := range feeTiers(extraFees) {
+		pool, liquidity, err := f.pool(ctx, tokenA, tokenB, big.NewInt(fee))
+		if err != nil {
+			return 0, fmt.Errorf("fee tier %d: %w", fee, err)
+		}
+		if pool == (common.Address{}) || liquidity.Sign() == 0 {
+			continue
+		}
+
+		score := liquidity
+		if byQuote {
+			quoter := NewUniswapV3Quoter(f.caller, f.quoter, f.weth, DirectRoute(big.NewInt(fee)))
+			if score, err = quoter.QuoteAmountOut(ctx, params); err != nil {
+				log.Printf("Fail to quote fee tier: fee=%d pool=%v err=%v", fee, pool, err)
+				continue
+			}
+			log.Printf("Found pool: fee=%d pool=%v liquidity=%v amountOut=%v", fee, pool, liquidity, score)
+		} else {
+			log.Printf("Found pool: fee=%d pool=%v liquidity=%v", fee, pool, liquidity)
+		}
+
+		if best == nil || score.Cmp(best) > 0 {
+			bestFee, best = fee, score
+		}
+	}
+
+	if best == nil {
+		return 0, errNoPool
+	}
+
+	return bestFee, nil
+}
+
+func (f *FeeTierFinder) pool(
+	ctx context.Context, tokenA, tokenB common.Address, fee *big.Int,
+) (common.Address, *big.Int, error) {
+	data, err := blockchain.EncodeGetPool(tokenA, tokenB, fee)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("encode get pool: %w", err)
+	}
+
+	res, err := f.caller.CallContract(ctx, callMsg(f.factory, data), nil)
+	if err != nil {
+		return common.Address{}, nil, fmt.Errorf("call get pool: %w", err)
+	}
+
+	pool, err := blockchain.DecodeGetPool(res)
+	if err != nil || pool == (common.Address{}) {
+		return pool, nil, err
+	}
+
+	if data, err = blockchain.EncodeLiquidity(); err != nil {
+		return common.Address{}, nil, fmt.Errorf("encode liquidity: %w", err)
+	}
+
+	if res, err = f.caller.CallContract(ctx, callMsg(pool, data), nil); err != nil {
+		return common.Address{}, nil, fmt.Errorf("call liquidity: %w", err)
+	}
+
+	liquidity, err := blockchain.DecodeLiquidity(res)
+	return pool, liquidity, err
+}
+
+// feeTiers returns the standard fee tiers followed by the extra ones not
+// among them.
+func feeTiers(extraFees []int64) []int64 {
+	fees := append([]int64(nil), StandardFeeTiers...)
+	for _, fee := range extraFees {
+		if !slices.Contains(fees, fee) {
+			fees = append(fees, fee)
+		}
+	}
+
+	return fees
+}
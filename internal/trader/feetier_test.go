@@ -0,0 +1,83 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePools answers getPool with the pool of each fee, liquidity with the
+// liquidity of each pool and QuoterV2 quotes with the amount out of each fee.
+type fakePools struct {
+	factory    common.Address
+	quoter     common.Address
+	pools      map[int64]common.Address
+	liquidity  map[common.Address]int64
+	amountsOut map[int64]int64
+}
+
+func (p *fakePools) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	for fee, pool := range p.pools {
+		switch *msg.To {
+		case p.factory:
+			if new(big.Int).SetBytes(msg.Data[4+2*32:]).Int64() == fee {
+				return common.LeftPadBytes(pool.Bytes(), 32), nil
+			}
+		case pool:
+			return int256Word(p.liquidity[pool]), nil
+		case p.quoter:
+			if new(big.Int).SetBytes(msg.Data[4+3*32:4+4*32]).Int64() == fee {
+				return append(int256Word(p.amountsOut[fee]), make([]byte, 3*32)...), nil
+			}
+		}
+	}
+
+	return make([]byte, 32), nil
+}
+
+func TestFeeTierFinder(t *testing.T) {
+	pool500 := common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	pool3000 := common.HexToAddress("0x8ad599c3A0ff1De082011EFDDc58f1908eb6e6D8")
+	pool7500 := common.HexToAddress("0x7BeA39867e4169DBe237d55C8242a8f2fcDcc387")
+	caller := &fakePools{
+		factory: common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984"),
+		quoter:  common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e"),
+		pools:   map[int64]common.Address{500: pool500, 3000: pool3000, 7500: pool7500},
+		liquidity: map[common.Address]int64{
+			pool500:  1e18,
+			pool3000: 2e18,
+		},
+		amountsOut: map[int64]int64{500: 2500_000000, 3000: 2400_000000},
+	}
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4f27eAD9083C756Cc2")
+	params := SwapParams{
+		InputToken:  ethAddress,
+		OutputToken: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		AmountIn:    big.NewInt(1e18),
+	}
+
+	// Without quoter, the pool with the most liquidity wins.
+	fee, err := NewFeeTierFinder(caller, caller.factory, weth, common.Address{}).Find(context.Background(), params, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 3000, fee)
+
+	// The best quote wins over liquidity.
+	fee, err = NewFeeTierFinder(caller, caller.factory, weth, caller.quoter).Find(context.Background(), params, nil)
+	require.NoError(t, err)
+	require.EqualValues(t, 500, fee)
+
+	// Custom tiers are tried too.
+	caller.liquidity[pool7500] = 3e18
+	fee, err = NewFeeTierFinder(caller, caller.factory, weth, common.Address{}).Find(
+		context.Background(), params, []int64{7500})
+	require.NoError(t, err)
+	require.EqualValues(t, 7500, fee)
+
+	caller.pools = nil
+	_, err = NewFeeTierFinder(caller, caller.factory, weth, common.Address{}).Find(context.Background(), params, nil)
+	require.ErrorIs(t, err, errNoPool)
+}
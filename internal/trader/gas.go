@@ -0,0 +1,157 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/gasprice"
+)
+
+// HeaderClient is implemented by ethclient.Client.
+type HeaderClient interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// SupportsDynamicFeeTx reports whether the chain has EIP-1559 enabled, which
+// the latest header tells by its base fee.
+func SupportsDynamicFeeTx(ctx context.Context, client HeaderClient) (bool, error) {
+	header, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("get latest header: %w", err)
+	}
+
+	return header.BaseFee != nil, nil
+}
+
+// WithLegacyTx sends legacy transactions, priced at the legacy gas price of
+// the gas quotes, instead of dynamic fee ones.
+func WithLegacyTx() Option {
+	return func(t *Trader) {
+		t.legacyTx = true
+	}
+}
+
+// gasFees returns the fee cap and tip of a transaction using gasLimit, which
+// are both the gas price for legacy transactions.
+func (t *Trader) gasFees(gasLimit uint64, quote gasprice.GasQuote, maxGasFee *big.Int) (*big.Int, *big.Int) {
+	if !t.legacyTx {
+		return gasPriceWithCap(gasLimit, quote, t.cfg.GasTipMultiplier, maxGasFee)
+	}
+
+	gasPrice := legacyGasPrice(quote, t.cfg.GasTipMultiplier)
+	if maxGasFee != nil {
+		maxGasPrice := new(big.Int).Div(maxGasFee, new(big.Int).SetUint64(gasLimit))
+		if gasPrice.Cmp(maxGasPrice) > 0 {
+			gasPrice = maxGasPrice
+		}
+	}
+
+	return new(big.Int).Set(gasPrice), new(big.Int).Set(gasPrice)
+}
+
+// legacyGasPrice returns the legacy gas price of the quote with the part
+// above the base fee, which is the tip, multiplied by tipMultiplier.
+func legacyGasPrice(quote gasprice.GasQuote, tipMultiplier float64) *big.Int {
+	gasPrice := quote.GasPrice
+	if gasPrice == nil {
+		gasPrice = quote.MaxFee
+	}
+
+	baseFee := big.NewInt(0)
+	if quote.BaseFee != nil && quote.BaseFee.Cmp(gasPrice) < 0 {
+		baseFee = quote.BaseFee
+	}
+	tip := new(big.Int).Sub(gasPrice, baseFee)
+
+	return tip.Add(baseFee, gasprice.MulFloat(tip, tipMultiplier))
+}
+
+// cappedGasFees returns the fee cap and tip of the quote, the fee cap being
+// lowered to keep the gas fee within maxGasFee. Unlike gasFees, it never
+// raises the fees up to maxGasFee.
+func (t *Trader) cappedGasFees(gasLimit uint64, quote gasprice.GasQuote, maxGasFee *big.Int) (*big.Int, *big.Int) {
+	gasFeeCap, gasTipCap := t.gasFees(gasLimit, quote, nil)
+	if maxGasFee == nil {
+		return gasFeeCap, gasTipCap
+	}
+
+	maxGasPrice := new(big.Int).Div(maxGasFee, new(big.Int).SetUint64(gasLimit))
+	if gasFeeCap.Cmp(maxGasPrice) > 0 {
+		gasFeeCap = maxGasPrice
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasTipCap = new(big.Int).Set(gasFeeCap)
+	}
+
+	return gasFeeCap, gasTipCap
+}
+
+// newTx returns a dynamic fee transaction, or a legacy one priced at
+// gasFeeCap when legacy transactions are used.
+func (t *Trader) newTx(
+	nonce uint64, gasFeeCap, gasTipCap *big.Int, gas uint64, to *common.Address, data []byte, value *big.Int,
+) *types.Transaction {
+	if t.legacyTx {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: gasFeeCap,
+			Gas:      gas,
+			To:       to,
+			Value:    value,
+			Data:     data,
+		})
+	}
+
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   t.chainID,
+		Nonce:     nonce,
+		GasTipCap: gasTipCap,
+		GasFeeCap: gasFeeCap,
+		Gas:       gas,
+		To:        to,
+		Data:      data,
+		Value:     value,
+	})
+}
+
+// WithTierGasPricers sets the gas pricers of the accounts overriding the gas
+// tier, keyed by tier.
+func WithTierGasPricers(gasPricers map[string]gasprice.GasPricer) Option {
+	return func(t *Trader) {
+		t.tierGasPricers = gasPricers
+	}
+}
+
+// gasPricerOf returns the gas pricer of the account gas tier, or the default
+// one.
+func (t *Trader) gasPricerOf(account config.Account) gasprice.GasPricer {
+	if gasPricer, ok := t.tierGasPricers[account.GasTier]; ok && account.GasTier != "" {
+		return gasPricer
+	}
+
+	return t.gasPricer
+}
+
+// gasPriceWithCap returns the max fee and tip of the quote, the tip times
+// tipMultiplier, or the max fee that keeps the gas fee within maxGasFee.
+func gasPriceWithCap(
+	gasLimit uint64, quote gasprice.GasQuote, tipMultiplier float64, maxGasFee *big.Int,
+) (*big.Int, *big.Int) {
+	if maxGasFee != nil {
+		maxGasPrice := new(big.Int).Div(maxGasFee, new(big.Int).SetUint64(gasLimit))
+		return maxGasPrice, new(big.Int).Set(maxGasPrice)
+	}
+
+	gasTipCap := gasprice.MulFloat(quote.TipCap, tipMultiplier)
+	maxGasPrice := new(big.Int).Set(quote.MaxFee)
+	if maxGasPrice.Cmp(gasTipCap) < 0 {
+		maxGasPrice.Set(gasTipCap)
+	}
+
+	return maxGasPrice, gasTipCap
+}
@@ -0,0 +1,126 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"strings"
+
+	"github.com/hiepnv90/ilo/internal/clients/krystal"
+)
+
+// KrystalClient is implemented by krystal.Client.
+type KrystalClient interface {
+	GetAllRates(
+		srcToken string, dstToken string, srcAmount *big.Int, platformWallet string, userAddress string,
+	) (krystal.RatesResponse, error)
+	BuildTx(
+		srcToken, dstToken string, srcAmount, minDestAmount *big.Int,
+		platformWallet, userAddress, hint string, gasPrice *big.Int, nonce uint64,
+		skipBalanceCheck bool,
+	) (krystal.BuildTxResponse, error)
+}
+
+// KrystalBuilder routes swaps through the best rate returned by Krystal and
+// falls back to another builder, usually the direct uniswap v3 path, when
+// Krystal fails.
+type KrystalBuilder struct {
+	client         KrystalClient
+	platformWallet string
+	fallback       CalldataBuilder
+}
+
+func NewKrystalBuilder(client KrystalClient, platformWallet string, fallback CalldataBuilder) *KrystalBuilder {
+	return &KrystalBuilder{
+		client:         client,
+		platformWallet: platformWallet,
+		fallback:       fallback,
+	}
+}
+
+func (b *KrystalBuilder) BuildSwap(ctx context.Context, params SwapParams) (Call, error) {
+	call, err := b.buildSwap(params)
+	if err == nil {
+		return call, nil
+	}
+
+	if b.fallback == nil {
+		return Call{}, err
+	}
+
+	log.Printf("Fail to build krystal swap, fall back to direct swap: sender=%v error=%v", params.From, err)
+	return b.fallback.BuildSwap(ctx, params)
+}
+
+func (b *KrystalBuilder) buildSwap(params SwapParams) (Call, error) {
+	if params.ExactOutput {
+		return Call{}, errors.New("krystal does not support exact output")
+	}
+	if params.Recipient != params.From {
+		return Call{}, errors.New("krystal does not support a recipient different from the sender")
+	}
+	if !params.Deadline.IsZero() {
+		return Call{}, errors.New("krystal does not support a swap deadline")
+	}
+	if params.Permit != nil {
+		return Call{}, errors.New("krystal does not support permit2")
+	}
+
+	srcToken := strings.ToLower(params.InputToken.Hex())
+	dstToken := strings.ToLower(params.OutputToken.Hex())
+	userAddress := strings.ToLower(params.From.Hex())
+
+	rates, err := b.client.GetAllRates(srcToken, dstToken, params.AmountIn, b.platformWallet, userAddress)
+	if err != nil {
+		return Call{}, fmt.Errorf("get all rates: %w", err)
+	}
+
+	best, err := bestRate(rates.Rates)
+	if err != nil {
+		return Call{}, err
+	}
+
+	minAmountOut := params.MinAmountOut
+	if minAmountOut == nil {
+		minAmountOut = big.NewInt(0)
+	}
+
+	res, err := b.client.BuildTx(
+		srcToken, dstToken, params.AmountIn, minAmountOut,
+		b.platformWallet, userAddress, best.Hint, params.GasPrice, params.Nonce, true,
+	)
+	if err != nil {
+		return Call{}, fmt.Errorf("build tx: %w", err)
+	}
+
+	log.Printf("Build krystal swap: sender=%v platform=%s amount=%s", params.From, best.Platform, best.Amount)
+	return Call{
+		To:    res.TxObject.To,
+		Data:  res.TxObject.Data,
+		Value: res.TxObject.Value.ToInt(),
+	}, nil
+}
+
+// bestRate returns the rate with the highest amount out.
+func bestRate(rates []krystal.Rate) (krystal.Rate, error) {
+	var best krystal.Rate
+	var bestAmount *big.Int
+	for _, rate := range rates {
+		amount, ok := new(big.Int).SetString(rate.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		if bestAmount == nil || amount.Cmp(bestAmount) > 0 {
+			best, bestAmount = rate, amount
+		}
+	}
+
+	if bestAmount == nil {
+		return krystal.Rate{}, errors.New("no rate available")
+	}
+
+	return best, nil
+}
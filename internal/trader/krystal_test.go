@@ -0,0 +1,86 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/clients/krystal"
+)
+
+type fakeKrystalClient struct {
+	rates []krystal.Rate
+	err   error
+	hint  string
+	nonce uint64
+}
+
+func (c *fakeKrystalClient) GetAllRates(string, string, *big.Int, string, string) (krystal.RatesResponse, error) {
+	return krystal.RatesResponse{Rates: c.rates}, c.err
+}
+
+func (c *fakeKrystalClient) BuildTx(
+	_, _ string, _, _ *big.Int, _, _, hint string, _ *big.Int, nonce uint64, _ bool,
+) (krystal.BuildTxResponse, error) {
+	c.hint, c.nonce = hint, nonce
+	return krystal.BuildTxResponse{
+		TxObject: krystal.Transaction{
+			To:    common.HexToAddress("0x70270c228c5b4279d1578799926873aa72446ccd"),
+			Value: hexutil.Big(*big.NewInt(1e18)),
+			Data:  []byte{1, 2, 3, 4},
+		},
+	}, nil
+}
+
+func TestKrystalBuilder(t *testing.T) {
+	cfg := testConfig()
+	fallback := NewUniswapV3Builder(
+		common.HexToAddress(cfg.RouterAddress),
+		common.HexToAddress(cfg.Weth),
+		DirectRoute(big.NewInt(int64(cfg.FeeTier))),
+	)
+	client := &fakeKrystalClient{
+		rates: []krystal.Rate{
+			{Amount: "2500000000", Hint: "0x01"},
+			{Amount: "2510000000", Hint: "0x02"},
+		},
+	}
+
+	from := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	params := SwapParams{
+		Nonce:        3,
+		From:         from,
+		Recipient:    from,
+		InputToken:   ethAddress,
+		OutputToken:  common.HexToAddress(cfg.OutputToken),
+		AmountIn:     big.NewInt(1e18),
+		MinAmountOut: big.NewInt(0),
+	}
+
+	builder := NewKrystalBuilder(client, "", fallback)
+	call, err := builder.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress("0x70270c228c5b4279d1578799926873aa72446ccd"), call.To)
+	require.Equal(t, "0x02", client.hint)
+	require.Equal(t, uint64(3), client.nonce)
+
+	client.err = errors.New("krystal is down")
+	call, err = builder.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress(cfg.RouterAddress), call.To)
+
+	client.err = nil
+	params.Deadline = time.Now().Add(time.Minute)
+	call, err = builder.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, common.HexToAddress(cfg.RouterAddress), call.To)
+
+	_, err = NewKrystalBuilder(client, "", nil).BuildSwap(context.Background(), params)
+	require.ErrorContains(t, err, "deadline")
+}
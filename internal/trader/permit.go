@@ -0,0 +1,72 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+// permitExpiration is how long a signed permit and the allowance it grants
+// stay valid, which covers presigning well ahead of the start.
+const permitExpiration = 24 * time.Hour
+
+// signPermit signs a Permit2 PermitSingle letting the router pull the input
+// amount from the account. No permit is needed when Permit2 is disabled, the
+// input is ETH or the router already has a valid Permit2 allowance.
+func (t *Trader) signPermit(
+	ctx context.Context, account config.Account, params SwapParams,
+) (*blockchain.PermitSingle, []byte, error) {
+	if t.cfg.Permit2Address == "" || isEth(params.InputToken) {
+		return nil, nil, nil
+	}
+
+	permit2 := common.HexToAddress(t.cfg.Permit2Address)
+	router := common.HexToAddress(t.cfg.RouterAddress)
+
+	data, err := blockchain.EncodePermit2Allowance(params.From, params.InputToken, router)
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := t.client.CallContract(ctx, callMsg(permit2, data), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("get permit2 allowance: %w", err)
+	}
+	allowance, err := blockchain.DecodePermit2Allowance(res)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode permit2 allowance: %w", err)
+	}
+
+	now := time.Now()
+	if allowance.Amount.Cmp(params.AmountIn) >= 0 && allowance.Expiration.Int64() > now.Add(tradeTimeout).Unix() {
+		return nil, nil, nil
+	}
+
+	expiration := big.NewInt(now.Add(permitExpiration).Unix())
+	permit := blockchain.PermitSingle{
+		Details: blockchain.PermitDetails{
+			Token:      params.InputToken,
+			Amount:     params.AmountIn,
+			Expiration: expiration,
+			Nonce:      allowance.Nonce,
+		},
+		Spender:     router,
+		SigDeadline: expiration,
+	}
+
+	signature, err := t.signer.SignHash(account, permit.Hash(t.chainID, permit2))
+	if err != nil {
+		return nil, nil, fmt.Errorf("sign permit: %w", err)
+	}
+
+	log.Printf("Sign permit: owner=%v token=%v spender=%v amount=%v nonce=%v",
+		params.From, params.InputToken, router, params.AmountIn, allowance.Nonce)
+
+	return &permit, signature, nil
+}
@@ -0,0 +1,72 @@
+package trader
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraderRunPermit2(t *testing.T) {
+	cfg := testConfig()
+	cfg.InputToken = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	cfg.OutputToken = "0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"
+	cfg.RouterAddress = "0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD"
+	cfg.Permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+	cfg.Accounts[0].InputAmount = big.NewInt(1000_000000)
+
+	permit2 := common.HexToAddress(cfg.Permit2Address)
+	client := newFakeChainClient()
+	client.callResults = map[common.Address][]byte{
+		common.HexToAddress(cfg.InputToken): int256Word(1e18), // allowance of permit2
+		permit2:                             append(append(int256Word(0), int256Word(0)...), int256Word(5)...),
+	}
+
+	trader := New(
+		cfg,
+		client,
+		fakeGasPricer{maxFee: 20e9, tipCap: 2e9},
+		NewKeystoreSigner(nil, big.NewInt(cfg.ChainID)),
+		NewUniversalRouterBuilder(
+			common.HexToAddress(cfg.RouterAddress),
+			common.HexToAddress(cfg.Weth),
+			DirectRoute(big.NewInt(int64(cfg.FeeTier))),
+		),
+	)
+
+	account := cfg.Accounts[0]
+	from := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	params := SwapParams{
+		From:        from,
+		InputToken:  common.HexToAddress(cfg.InputToken),
+		OutputToken: common.HexToAddress(cfg.OutputToken),
+		AmountIn:    account.InputAmount,
+	}
+	permit, signature, err := trader.signPermit(context.Background(), account, params)
+	require.NoError(t, err)
+	require.NotNil(t, permit)
+	require.Equal(t, big.NewInt(5), permit.Details.Nonce)
+	require.Equal(t, common.HexToAddress(cfg.RouterAddress), permit.Spender)
+
+	sig := bytes.Clone(signature)
+	sig[crypto.RecoveryIDOffset] -= 27
+	pub, err := crypto.SigToPub(permit.Hash(big.NewInt(cfg.ChainID), permit2).Bytes(), sig)
+	require.NoError(t, err)
+	require.Equal(t, from, crypto.PubkeyToAddress(*pub))
+
+	_, err = trader.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 1)
+	require.Equal(t, common.HexToAddress(cfg.RouterAddress), *client.sent[0].To())
+	require.True(t, bytes.Contains(client.sent[0].Data(), signature))
+
+	// A valid permit2 allowance needs no new permit.
+	client.callResults[permit2] = append(append(int256Word(1e18), int256Word(1<<40)...), int256Word(6)...)
+	permit, _, err = trader.signPermit(context.Background(), account, params)
+	require.NoError(t, err)
+	require.Nil(t, permit)
+}
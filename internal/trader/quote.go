@@ -0,0 +1,157 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+var errPriceTooHigh = errors.New("quoted price is higher than max price")
+
+// ContractCaller is implemented by ethclient.Client.
+type ContractCaller interface {
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Quoter quotes the amount out of a swap against the latest state.
+type Quoter interface {
+	QuoteAmountOut(ctx context.Context, params SwapParams) (*big.Int, error)
+}
+
+// WithQuoter sets the quoter used to derive the minimum return amount from
+// slippage_bps and to check max_price.
+func WithQuoter(quoter Quoter) Option {
+	return func(t *Trader) {
+		t.quoter = quoter
+	}
+}
+
+// UniswapV3Quoter quotes swaps along a route with QuoterV2.
+type UniswapV3Quoter struct {
+	caller ContractCaller
+	quoter common.Address
+	weth   common.Address
+	route  Route
+}
+
+func NewUniswapV3Quoter(caller ContractCaller, quoter, weth common.Address, route Route) *UniswapV3Quoter {
+	return &UniswapV3Quoter{
+		caller: caller,
+		quoter: quoter,
+		weth:   weth,
+		route:  route,
+	}
+}
+
+func (q *UniswapV3Quoter) QuoteAmountOut(ctx context.Context, params SwapParams) (*big.Int, error) {
+	tokens, fees, err := q.route.path(params.InputToken, params.OutputToken, q.weth)
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	if q.route.IsMultiHop() {
+		data, err = blockchain.EncodeQuoteExactInput(tokens, fees, params.AmountIn)
+	} else {
+		data, err = blockchain.EncodeQuoteExactInputSingle(tokens[0], tokens[1], params.AmountIn, fees[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode quote: %w", err)
+	}
+
+	res, err := q.caller.CallContract(ctx, callMsg(q.quoter, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("call quote: %w", err)
+	}
+
+	if q.route.IsMultiHop() {
+		quote, err := blockchain.DecodeQuoteExactInput(res)
+		if err != nil {
+			return nil, fmt.Errorf("decode quote: %w", err)
+		}
+		return quote.AmountOut, nil
+	}
+
+	quote, err := blockchain.DecodeQuoteExactInputSingle(res)
+	if err != nil {
+		return nil, fmt.Errorf("decode quote: %w", err)
+	}
+
+	return quote.AmountOut, nil
+}
+
+// minReturnAmount returns the configured minimum return amount of the
+// account, raised to the live quote minus slippage_bps when slippage is set.
+// It fails if the quoted price is above max_price.
+func (t *Trader) minReturnAmount(
+	ctx context.Context, account config.Account, params SwapParams,
+) (*big.Int, error) {
+	minReturnAmount := t.cfg.MinReturnAmount
+	if account.MinReturnAmount != nil {
+		minReturnAmount = account.MinReturnAmount
+	} else if minReturnAmount == nil {
+		minReturnAmount = big.NewInt(0)
+	}
+
+	if t.cfg.SlippageBPS <= 0 && t.cfg.MaxPrice <= 0 {
+		return minReturnAmount, nil
+	}
+
+	if t.quoter == nil {
+		return nil, errors.New("quoter is required for slippage_bps and max_price")
+	}
+
+	amountOut, err := t.quoter.QuoteAmountOut(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("quote amount out: %w", err)
+	}
+	if amountOut.Sign() == 0 {
+		return nil, errors.New("quoted amount out is zero")
+	}
+
+	if t.cfg.MaxPrice > 0 {
+		price, err := t.price(ctx, params.AmountIn, amountOut)
+		if err != nil {
+			return nil, err
+		}
+		if price > t.cfg.MaxPrice {
+			return nil, fmt.Errorf("%w: price=%v maxPrice=%v", errPriceTooHigh, price, t.cfg.MaxPrice)
+		}
+	}
+
+	if t.cfg.SlippageBPS > 0 {
+		quoteMin := new(big.Int).Mul(amountOut, big.NewInt(10_000-t.cfg.SlippageBPS))
+		quoteMin.Div(quoteMin, big.NewInt(10_000))
+		if quoteMin.Cmp(minReturnAmount) > 0 {
+			minReturnAmount = quoteMin
+		}
+	}
+
+	log.Printf("Quote swap: inputAmount=%v amountOut=%v minReturnAmount=%v",
+		params.AmountIn, amountOut, minReturnAmount)
+
+	return minReturnAmount, nil
+}
+
+// price returns the price in input token per output token of the amounts.
+func (t *Trader) price(ctx context.Context, amountIn, amountOut *big.Int) (float64, error) {
+	decimalsIn, err := t.tokenDecimals(ctx, common.HexToAddress(t.cfg.InputToken))
+	if err != nil {
+		return 0, fmt.Errorf("get input token decimals: %w", err)
+	}
+	decimalsOut, err := t.tokenDecimals(ctx, common.HexToAddress(t.cfg.OutputToken))
+	if err != nil {
+		return 0, fmt.Errorf("get output token decimals: %w", err)
+	}
+
+	price, _ := new(big.Float).Quo(toUnits(amountIn, decimalsIn), toUnits(amountOut, decimalsOut)).Float64()
+	return price, nil
+}
@@ -0,0 +1,80 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeQuoter struct {
+	amountOut *big.Int
+}
+
+func (q fakeQuoter) QuoteAmountOut(context.Context, SwapParams) (*big.Int, error) {
+	return q.amountOut, nil
+}
+
+type fakeContractCaller struct {
+	msgs   []ethereum.CallMsg
+	result []byte
+}
+
+func (c *fakeContractCaller) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	c.msgs = append(c.msgs, msg)
+	return c.result, nil
+}
+
+func TestUniswapV3Quoter(t *testing.T) {
+	var result []byte
+	for _, v := range []int64{2500_000000, 1, 2, 90000} {
+		result = append(result, int256Word(v)...)
+	}
+	caller := &fakeContractCaller{result: result}
+	quoterAddress := common.HexToAddress("0x61fFE014bA17989E743c5F6cB21bF9697530B21e")
+
+	quoter := NewUniswapV3Quoter(caller, quoterAddress,
+		common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2"), DirectRoute(big.NewInt(500)))
+	amountOut, err := quoter.QuoteAmountOut(context.Background(), SwapParams{
+		InputToken:  ethAddress,
+		OutputToken: common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48"),
+		AmountIn:    big.NewInt(1e18),
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2500_000000), amountOut)
+	require.Equal(t, quoterAddress, *caller.msgs[0].To)
+}
+
+func TestMinReturnAmount(t *testing.T) {
+	cfg := testConfig()
+	cfg.SlippageBPS = 100
+	cfg.MinReturnAmount = big.NewInt(2000_000000)
+	client := newFakeChainClient()
+	client.callResult = int256Word(6)
+
+	trader := newTestTrader(cfg, client)
+	WithQuoter(fakeQuoter{amountOut: big.NewInt(2500_000000)})(trader)
+
+	params := SwapParams{AmountIn: big.NewInt(1e18)}
+	minReturnAmount, err := trader.minReturnAmount(context.Background(), cfg.Accounts[0], params)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2475_000000), minReturnAmount)
+
+	// The configured amount wins when it is higher than the quote.
+	trader.cfg.MinReturnAmount = big.NewInt(2490_000000)
+	minReturnAmount, err = trader.minReturnAmount(context.Background(), cfg.Accounts[0], params)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2490_000000), minReturnAmount)
+
+	// 1 ETH for 2500 USDC is a price of 0.0004 ETH per USDC.
+	trader.cfg.MaxPrice = 0.00039
+	_, err = trader.minReturnAmount(context.Background(), cfg.Accounts[0], params)
+	require.ErrorIs(t, err, errPriceTooHigh)
+
+	trader.cfg.MaxPrice = 0.00041
+	_, err = trader.minReturnAmount(context.Background(), cfg.Accounts[0], params)
+	require.NoError(t, err)
+}
@@ -0,0 +1,155 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+var errMaxGasFeeReached = errors.New("max gas fee reached")
+
+// waitMined waits until the transaction or one of its replacements is mined
+// and records the landed one in res. When replace_after_blocks is set, the
+// last sent transaction is re-signed with bumped fees every time it stays
+// pending for that many blocks, and the wait is measured in blocks so that
+// slow blocks leave room for every replacement. A swap with a deadline is
+// neither replaced nor waited for once the deadline has passed, as it can
+// only revert.
+func (t *Trader) waitMined(ctx context.Context, res *Result, signedTx *types.Transaction) error {
+	maxReplacements := t.cfg.MaxReplacements
+	if maxReplacements <= 0 {
+		maxReplacements = defaultMaxReplacements
+	}
+
+	canReplace := t.cfg.ReplaceAfterBlocks > 0
+	var sentBlock, lastBlock uint64
+	if canReplace {
+		var err error
+		sentBlock, err = t.client.BlockNumber(ctx)
+		if err != nil {
+			return fmt.Errorf("get block number: %w", err)
+		}
+		lastBlock = sentBlock + t.cfg.ReplaceAfterBlocks*uint64(maxReplacements+1) + replaceSlackBlocks
+	}
+
+	sent := []*types.Transaction{signedTx}
+	var deadline time.Time
+	switch {
+	case !res.Deadline.IsZero():
+		deadline = res.Deadline.Add(deadlineGracePeriod)
+	case !canReplace:
+		deadline = time.Now().Add(defaultDeadlineTime)
+	}
+
+	ticker := time.NewTicker(t.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, tx := range sent {
+			receipt, err := t.client.TransactionReceipt(ctx, tx.Hash())
+			if err == nil {
+				res.TxHash = tx.Hash()
+				res.Receipt = receipt
+				log.Printf("Transaction mined: hash=%v replacements=%d", tx.Hash(), len(sent)-1)
+				return nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				return fmt.Errorf("error fetching receipt: %v", err)
+			}
+		}
+
+		var blockNumber uint64
+		if lastBlock > 0 {
+			var err error
+			if blockNumber, err = t.client.BlockNumber(ctx); err != nil {
+				return fmt.Errorf("get block number: %w", err)
+			}
+		}
+
+		if canReplace && len(sent) <= maxReplacements && (res.Deadline.IsZero() || time.Now().Before(res.Deadline)) &&
+			blockNumber >= sentBlock+t.cfg.ReplaceAfterBlocks {
+			replacement, err := t.replace(ctx, res.Account, sent[len(sent)-1])
+			switch {
+			case errors.Is(err, errMaxGasFeeReached):
+				log.Printf("Stop replacing transaction: hash=%v error=%v", res.TxHash, err)
+				canReplace = false
+			case err != nil:
+				log.Printf("Fail to replace transaction: hash=%v error=%v", res.TxHash, err)
+			default:
+				sent = append(sent, replacement)
+				res.TxHash = replacement.Hash()
+				res.TxHashes = append(res.TxHashes, replacement.Hash())
+				sentBlock = blockNumber
+			}
+		}
+
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return fmt.Errorf("transaction not mined by %s", deadline.Format(time.RFC3339))
+		}
+		if deadline.IsZero() && blockNumber >= lastBlock {
+			return fmt.Errorf("transaction not mined by block %d", lastBlock)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// replace re-signs tx with the same nonce and fees bumped by
+// replace_bump_percent, capped by the account max gas fee, and broadcasts it.
+func (t *Trader) replace(
+	ctx context.Context, account config.Account, tx *types.Transaction,
+) (*types.Transaction, error) {
+	bumpPercent := t.cfg.ReplaceBumpPercent
+	if bumpPercent < minReplacementBumpPercent {
+		bumpPercent = minReplacementBumpPercent
+	}
+
+	gasFeeCap := bumpFee(tx.GasFeeCap(), bumpPercent)
+	gasTipCap := bumpFee(tx.GasTipCap(), bumpPercent)
+	if account.MaxGasFee != nil {
+		maxGasPrice := new(big.Int).Div(account.MaxGasFee, new(big.Int).SetUint64(tx.Gas()))
+		if gasFeeCap.Cmp(maxGasPrice) > 0 {
+			gasFeeCap = maxGasPrice
+		}
+		if gasFeeCap.Cmp(bumpFee(tx.GasFeeCap(), minReplacementBumpPercent)) < 0 {
+			return nil, errMaxGasFeeReached
+		}
+	}
+	if gasTipCap.Cmp(gasFeeCap) > 0 {
+		gasTipCap = new(big.Int).Set(gasFeeCap)
+	}
+
+	signedTx, err := t.signer.SignTx(
+		account, t.newTx(tx.Nonce(), gasFeeCap, gasTipCap, tx.Gas(), tx.To(), tx.Data(), tx.Value()))
+	if err != nil {
+		return nil, fmt.Errorf("sign replacement: %w", err)
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, tradeTimeout)
+	defer cancel()
+	if err = t.broadcaster.SendTransactions(sendCtx, []*types.Transaction{signedTx})[0].Err; err != nil {
+		return nil, fmt.Errorf("send replacement: %w", err)
+	}
+
+	log.Printf("Replace transaction: oldHash=%v newHash=%v gasFeeCap=%v gasTipCap=%v",
+		tx.Hash(), signedTx.Hash(), gasFeeCap, gasTipCap)
+
+	return signedTx, nil
+}
+
+func bumpFee(fee *big.Int, percent int64) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(100+percent))
+	return bumped.Div(bumped, big.NewInt(100))
+}
@@ -0,0 +1,202 @@
+package trader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"text/tabwriter"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+const ethDecimals = 18
+
+// decodeFill sets the amounts swapped by the account from the receipt logs.
+// The amount out is what the recipient received according to the output
+// token Transfer logs, falling back to the last pool Swap log when nothing
+// was transferred, e.g. when the output is unwrapped to ETH.
+func (t *Trader) decodeFill(res *Result) {
+	tokenOut := toTokenAddress(common.HexToAddress(t.cfg.OutputToken), common.HexToAddress(t.cfg.Weth))
+	recipient := recipientOf(res.Account, res.Address)
+
+	var swaps []blockchain.SwapEvent
+	var received *big.Int
+	for _, l := range res.Receipt.Logs {
+		if len(l.Topics) == 0 {
+			continue
+		}
+
+		switch l.Topics[0] {
+		case blockchain.SwapTopic(), blockchain.V2SwapTopic(), blockchain.AerodromeSwapTopic():
+			decode := blockchain.DecodeSwap
+			switch l.Topics[0] {
+			case blockchain.V2SwapTopic():
+				decode = blockchain.DecodeV2Swap
+			case blockchain.AerodromeSwapTopic():
+				decode = blockchain.DecodeAerodromeSwap
+			}
+			swap, err := decode(*l)
+			if err != nil {
+				log.Printf("Fail to decode swap log: transactionHash=%v error=%v", res.TxHash, err)
+				continue
+			}
+			swaps = append(swaps, swap)
+		case blockchain.TransferTopic():
+			if l.Address != tokenOut {
+				continue
+			}
+			transfer, err := blockchain.DecodeTransfer(*l)
+			if err != nil {
+				log.Printf("Fail to decode transfer log: transactionHash=%v error=%v", res.TxHash, err)
+				continue
+			}
+			if transfer.To == recipient {
+				if received == nil {
+					received = new(big.Int)
+				}
+				received.Add(received, transfer.Value)
+			}
+		}
+	}
+
+	// Amounts paid to a pool are positive and amounts paid by a pool are
+	// negative, so the input is the positive amount of the first hop and the
+	// output is the negative amount of the last hop.
+	if len(swaps) > 0 {
+		first, last := swaps[0], swaps[len(swaps)-1]
+		if first.Amount0.Sign() > 0 {
+			res.AmountIn = first.Amount0
+		} else {
+			res.AmountIn = first.Amount1
+		}
+		if last.Amount0.Sign() < 0 {
+			res.AmountOut = new(big.Int).Neg(last.Amount0)
+		} else {
+			res.AmountOut = new(big.Int).Neg(last.Amount1)
+		}
+	}
+	if received != nil {
+		res.AmountOut = received
+	}
+
+	log.Printf("Trade filled: transactionHash=%v amountIn=%v amountOut=%v gasUsed=%d effectiveGasPrice=%v",
+		res.TxHash, res.AmountIn, res.AmountOut, res.Receipt.GasUsed, res.Receipt.EffectiveGasPrice)
+}
+
+// fillPrices sets the effective price, in input token per output token, of
+// every result with both amounts known.
+func (t *Trader) fillPrices(ctx context.Context, results []Result) {
+	var decimalsIn, decimalsOut uint8
+	var loaded bool
+	for i := range results {
+		res := &results[i]
+		if res.AmountIn == nil || res.AmountOut == nil || res.AmountOut.Sign() == 0 {
+			continue
+		}
+
+		if !loaded {
+			var err error
+			decimalsIn, err = t.tokenDecimals(ctx, common.HexToAddress(t.cfg.InputToken))
+			if err != nil {
+				log.Printf("Fail to get input token decimals: error=%v", err)
+				return
+			}
+			decimalsOut, err = t.tokenDecimals(ctx, common.HexToAddress(t.cfg.OutputToken))
+			if err != nil {
+				log.Printf("Fail to get output token decimals: error=%v", err)
+				return
+			}
+			loaded = true
+		}
+
+		price := new(big.Float).Quo(toUnits(res.AmountIn, decimalsIn), toUnits(res.AmountOut, decimalsOut))
+		res.Price, _ = price.Float64()
+	}
+}
+
+func (t *Trader) tokenDecimals(ctx context.Context, token common.Address) (uint8, error) {
+	if isEth(token) {
+		return ethDecimals, nil
+	}
+
+	data, err := blockchain.EncodeDecimals()
+	if err != nil {
+		return 0, err
+	}
+
+	res, err := t.client.CallContract(ctx, callMsg(token, data), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	return blockchain.DecodeDecimals(res)
+}
+
+// WriteSummary writes a table of the results of all accounts to w.
+func WriteSummary(w io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACCOUNT\tTX HASH\tSTATUS\tAMOUNT IN\tAMOUNT OUT\tPRICE\tGAS USED\tGAS PRICE (GWEI)")
+
+	totalIn, totalOut := new(big.Int), new(big.Int)
+	weightedPrice := new(big.Float)
+	for _, r := range results {
+		status := "success"
+		if r.Err != nil {
+			status = "failed"
+		}
+
+		var gasUsed, gasPrice string
+		if r.Receipt != nil {
+			gasUsed = fmt.Sprint(r.Receipt.GasUsed)
+			gasPrice = toUnits(r.Receipt.EffectiveGasPrice, gweiDecimals).Text('f', 3)
+		}
+
+		fmt.Fprintf(tw, "%v\t%v\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			r.Address.Hex(), r.TxHash.Hex(), status,
+			formatAmount(r.AmountIn), formatAmount(r.AmountOut), formatPrice(r.Price), gasUsed, gasPrice)
+
+		if r.Err == nil && r.AmountIn != nil && r.AmountOut != nil {
+			totalIn.Add(totalIn, r.AmountIn)
+			totalOut.Add(totalOut, r.AmountOut)
+			weightedPrice.Add(weightedPrice,
+				new(big.Float).Mul(big.NewFloat(r.Price), new(big.Float).SetInt(r.AmountOut)))
+		}
+	}
+
+	var avgPrice float64
+	if totalOut.Sign() > 0 {
+		avgPrice, _ = new(big.Float).Quo(weightedPrice, new(big.Float).SetInt(totalOut)).Float64()
+	}
+	fmt.Fprintf(tw, "TOTAL\t\t\t%v\t%v\t%s\t\t\n", totalIn, totalOut, formatPrice(avgPrice))
+
+	return tw.Flush()
+}
+
+func toUnits(amount *big.Int, decimals uint8) *big.Float {
+	if amount == nil {
+		return new(big.Float)
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	return new(big.Float).Quo(new(big.Float).SetInt(amount), new(big.Float).SetInt(scale))
+}
+
+func formatAmount(amount *big.Int) string {
+	if amount == nil {
+		return "-"
+	}
+
+	return amount.String()
+}
+
+func formatPrice(price float64) string {
+	if price == 0 {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.8g", price)
+}
@@ -0,0 +1,107 @@
+package trader
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"slices"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+func int256Word(v int64) []byte {
+	return math.U256Bytes(big.NewInt(v))
+}
+
+func TestDecodeFill(t *testing.T) {
+	cfg := testConfig()
+	trader := newTestTrader(cfg, newFakeChainClient())
+
+	recipient := common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23")
+	pool := common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	router := common.HexToAddress(cfg.RouterAddress)
+
+	var swapData []byte
+	for _, v := range []int64{-2500_000000, 1e18, 1, 1, 0} {
+		swapData = append(swapData, int256Word(v)...)
+	}
+
+	res := Result{
+		Account: cfg.Accounts[0],
+		Address: recipient,
+		Receipt: &types.Receipt{
+			Logs: []*types.Log{
+				{
+					Address: pool,
+					Topics: []common.Hash{
+						blockchain.SwapTopic(),
+						common.BytesToHash(router.Bytes()),
+						common.BytesToHash(recipient.Bytes()),
+					},
+					Data: swapData,
+				},
+				{
+					Address: common.HexToAddress(cfg.OutputToken),
+					Topics: []common.Hash{
+						blockchain.TransferTopic(),
+						common.BytesToHash(pool.Bytes()),
+						common.BytesToHash(recipient.Bytes()),
+					},
+					Data: int256Word(2490_000000),
+				},
+			},
+			GasUsed:           100_000,
+			EffectiveGasPrice: big.NewInt(10_000_000_000),
+		},
+	}
+
+	trader.decodeFill(&res)
+	require.Equal(t, big.NewInt(1e18), res.AmountIn)
+	require.Equal(t, big.NewInt(2490_000000), res.AmountOut)
+
+	// Without a transfer to the recipient, the amount out comes from the swap.
+	res.Receipt.Logs = res.Receipt.Logs[:1]
+	trader.decodeFill(&res)
+	require.Equal(t, big.NewInt(2500_000000), res.AmountOut)
+
+	// Aerodrome pools emit their own Swap event.
+	res.Receipt.Logs = []*types.Log{{
+		Address: common.HexToAddress("0xcDAC0d6c6C59727a65F871236188350531885C43"),
+		Topics: []common.Hash{
+			blockchain.AerodromeSwapTopic(),
+			common.BytesToHash(router.Bytes()),
+			common.BytesToHash(recipient.Bytes()),
+		},
+		Data: slices.Concat(int256Word(1e18), int256Word(0), int256Word(0), int256Word(2480_000000)),
+	}}
+	trader.decodeFill(&res)
+	require.Equal(t, big.NewInt(1e18), res.AmountIn)
+	require.Equal(t, big.NewInt(2480_000000), res.AmountOut)
+}
+
+func TestWriteSummary(t *testing.T) {
+	cfg := testConfig()
+	client := newFakeChainClient()
+	client.callResult = int256Word(6)
+	trader := newTestTrader(cfg, client)
+
+	results := []Result{
+		{AmountIn: big.NewInt(1e18), AmountOut: big.NewInt(2500_000000)},
+		{AmountIn: big.NewInt(2e18), AmountOut: big.NewInt(4000_000000)},
+	}
+	trader.fillPrices(context.Background(), results)
+	require.InDelta(t, 0.0004, results[0].Price, 1e-12)
+	require.InDelta(t, 0.0005, results[1].Price, 1e-12)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteSummary(&buf, results))
+	require.Contains(t, buf.String(), "TOTAL")
+	require.Contains(t, buf.String(), "6500000000")
+	require.Contains(t, buf.String(), "0.00046153846")
+}
@@ -0,0 +1,69 @@
+package trader
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Route is a Uniswap V3 path, the tokens swapped through and the fee of the
+// pool between each pair. A route without tokens goes straight from the input
+// token to the output token through the pool of its only fee.
+type Route struct {
+	Tokens []common.Address
+	Fees   []*big.Int
+}
+
+// DirectRoute swaps through the single pool of fee.
+func DirectRoute(fee *big.Int) Route {
+	return Route{Fees: []*big.Int{fee}}
+}
+
+// ParseRoute parses a route alternating tokens and fees, such as
+// [WETH, 500, USDC, 10000, SALE].
+func ParseRoute(hops []string) (Route, error) {
+	if len(hops) < 3 || len(hops)%2 == 0 {
+		return Route{}, fmt.Errorf("invalid route: %d hops, want tokens separated by fees", len(hops))
+	}
+
+	var route Route
+	for i, hop := range hops {
+		if i%2 == 0 {
+			if !common.IsHexAddress(hop) {
+				return Route{}, fmt.Errorf("invalid route token: %s", hop)
+			}
+			route.Tokens = append(route.Tokens, common.HexToAddress(hop))
+			continue
+		}
+
+		fee, ok := new(big.Int).SetString(hop, 10)
+		if !ok || fee.Sign() <= 0 || fee.BitLen() > 24 {
+			return Route{}, fmt.Errorf("invalid route fee: %s", hop)
+		}
+		route.Fees = append(route.Fees, fee)
+	}
+
+	return route, nil
+}
+
+// IsMultiHop reports whether the route goes through more than one pool.
+func (r Route) IsMultiHop() bool {
+	return len(r.Fees) > 1
+}
+
+// path returns the tokens and fees of the route from the input token to the
+// output token, ETH being swapped as WETH.
+func (r Route) path(inputToken, outputToken, weth common.Address) ([]common.Address, []*big.Int, error) {
+	inputToken, outputToken = toTokenAddress(inputToken, weth), toTokenAddress(outputToken, weth)
+	if len(r.Tokens) == 0 {
+		return []common.Address{inputToken, outputToken}, r.Fees, nil
+	}
+
+	if r.Tokens[0] != inputToken || r.Tokens[len(r.Tokens)-1] != outputToken {
+		return nil, nil, fmt.Errorf("route %v does not swap %v to %v",
+			r.Tokens, inputToken, outputToken)
+	}
+
+	return r.Tokens, r.Fees, nil
+}
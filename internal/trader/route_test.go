@@ -0,0 +1,60 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRoute(t *testing.T) {
+	weth := "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	usdc := "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+	sale := "0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"
+
+	route, err := ParseRoute([]string{weth, "500", usdc, "10000", sale})
+	require.NoError(t, err)
+	require.True(t, route.IsMultiHop())
+	require.Equal(t, []*big.Int{big.NewInt(500), big.NewInt(10000)}, route.Fees)
+
+	tokens, fees, err := route.path(ethAddress, common.HexToAddress(sale), common.HexToAddress(weth))
+	require.NoError(t, err)
+	require.Len(t, tokens, 3)
+	require.Len(t, fees, 2)
+
+	_, _, err = route.path(common.HexToAddress(usdc), common.HexToAddress(sale), common.HexToAddress(weth))
+	require.Error(t, err)
+
+	for _, hops := range [][]string{
+		{weth, "500"},
+		{weth, "500", usdc, sale},
+		{weth, usdc, sale},
+		{weth, "16777216", usdc},
+		{"weth", "500", usdc},
+	} {
+		_, err = ParseRoute(hops)
+		require.Error(t, err, hops)
+	}
+}
+
+func TestUniswapV3BuilderMultiHop(t *testing.T) {
+	cfg := testConfig()
+	cfg.OutputToken = "0x1f9840a85d5af5bf1d1762f925bdd85fc7c5c7a3"
+	route, err := ParseRoute([]string{
+		cfg.Weth, "500", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "10000", cfg.OutputToken,
+	})
+	require.NoError(t, err)
+
+	builder := NewUniswapV3Builder(common.HexToAddress(cfg.RouterAddress), common.HexToAddress(cfg.Weth), route)
+	call, err := builder.BuildSwap(context.Background(), SwapParams{
+		InputToken:   ethAddress,
+		OutputToken:  common.HexToAddress(cfg.OutputToken),
+		AmountIn:     big.NewInt(1e18),
+		MinAmountOut: big.NewInt(1),
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1e18), call.Value)
+	require.Equal(t, common.FromHex("0xb858183f"), call.Data[:4]) // exactInput
+}
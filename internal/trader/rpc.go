@@ -0,0 +1,210 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+const dialTimeout = 10 * time.Second
+
+//nolint:gochecknoglobals
+var knownTxErrors = []string{
+	"already known",
+	"known transaction",
+	"already imported",
+	"already exists",
+}
+
+// nonceTooLowError is also returned for a transaction the endpoint already
+// mined, which only counts as accepted if the endpoint knows its hash.
+const nonceTooLowError = "nonce too low"
+
+// TransactionReader is implemented by ethclient.Client.
+type TransactionReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+}
+
+// DialFastest dials every url and returns the client of the node answering
+// eth_blockNumber first. Nodes failing to answer are considered unhealthy.
+func DialFastest(ctx context.Context, urls []string) (*ethclient.Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no node rpc configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, dialTimeout)
+	defer cancel()
+
+	type dialResult struct {
+		url     string
+		client  *ethclient.Client
+		latency time.Duration
+		err     error
+	}
+
+	results := make(chan dialResult, len(urls))
+	for _, u := range urls {
+		go func(u string) {
+			start := time.Now()
+			client, err := ethclient.DialContext(ctx, u)
+			if err == nil {
+				if _, err = client.BlockNumber(ctx); err != nil {
+					client.Close()
+				}
+			}
+			results <- dialResult{url: u, client: client, latency: time.Since(start), err: err}
+		}(u)
+	}
+
+	var fastest *dialResult
+	var errs []error
+	for range urls {
+		r := <-results
+		switch {
+		case r.err != nil:
+			log.Printf("Node is unhealthy: url=%s error=%v", r.url, r.err)
+			errs = append(errs, fmt.Errorf("%s: %w", r.url, r.err))
+		case fastest == nil:
+			fastest = &r
+		default:
+			r.client.Close()
+		}
+	}
+
+	if fastest == nil {
+		return nil, errors.Join(errs...)
+	}
+
+	log.Printf("Use fastest node: url=%s latencyMs=%d", fastest.url, fastest.latency.Milliseconds())
+	return fastest.client, nil
+}
+
+// MultiSender sends every transaction to all endpoints concurrently and
+// succeeds as soon as one of them accepts it.
+type MultiSender struct {
+	urls    []string
+	senders []TransactionSender
+	closers []func()
+}
+
+func NewMultiSender(urls []string, senders []TransactionSender) *MultiSender {
+	return &MultiSender{
+		urls:    urls,
+		senders: senders,
+	}
+}
+
+// DialMultiSender dials every url to send transactions to.
+func DialMultiSender(ctx context.Context, urls []string) (*MultiSender, error) {
+	s := &MultiSender{}
+	for _, u := range urls {
+		client, err := ethclient.DialContext(ctx, u)
+		if err != nil {
+			s.Close()
+			return nil, fmt.Errorf("dial %s: %w", u, err)
+		}
+
+		s.urls = append(s.urls, u)
+		s.senders = append(s.senders, client)
+		s.closers = append(s.closers, client.Close)
+	}
+
+	return s, nil
+}
+
+func (s *MultiSender) Close() {
+	for _, closeFn := range s.closers {
+		closeFn()
+	}
+}
+
+type sendResult struct {
+	url string
+	err error
+}
+
+func (s *MultiSender) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	// Keep sending to slow endpoints after the first one accepts the
+	// transaction and the caller moves on.
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tradeTimeout)
+	results := make(chan sendResult, len(s.senders))
+	for i, sender := range s.senders {
+		go func(url string, sender TransactionSender) {
+			err := sender.SendTransaction(sendCtx, tx)
+			if isKnownTxError(err) || isKnownNonceTooLow(sendCtx, sender, tx, err) {
+				log.Printf("Transaction already known: endpoint=%s transactionHash=%v error=%v", url, tx.Hash(), err)
+				err = nil
+			}
+			results <- sendResult{url: url, err: err}
+		}(s.urls[i], sender)
+	}
+
+	var errs []error
+	for i := range s.senders {
+		var r sendResult
+		select {
+		case <-ctx.Done():
+			go drainSendResults(results, len(s.senders)-i, cancel)
+			return ctx.Err()
+		case r = <-results:
+		}
+
+		if r.err == nil {
+			log.Printf("Transaction accepted first: endpoint=%s transactionHash=%v", r.url, tx.Hash())
+			go drainSendResults(results, len(s.senders)-i-1, cancel)
+			return nil
+		}
+
+		log.Printf("Endpoint rejected transaction: endpoint=%s transactionHash=%v error=%v", r.url, tx.Hash(), r.err)
+		errs = append(errs, fmt.Errorf("%s: %w", r.url, r.err))
+	}
+
+	cancel()
+	return errors.Join(errs...)
+}
+
+// drainSendResults waits for the remaining sends to finish before releasing their context.
+func drainSendResults(ch <-chan sendResult, n int, cancel context.CancelFunc) {
+	for i := 0; i < n; i++ {
+		<-ch
+	}
+	cancel()
+}
+
+// isKnownNonceTooLow reports whether err is "nonce too low" for tx itself
+// rather than for another transaction that took its nonce.
+func isKnownNonceTooLow(ctx context.Context, sender TransactionSender, tx *types.Transaction, err error) bool {
+	if err == nil || !strings.Contains(strings.ToLower(err.Error()), nonceTooLowError) {
+		return false
+	}
+
+	reader, ok := sender.(TransactionReader)
+	if !ok {
+		return false
+	}
+
+	_, _, err = reader.TransactionByHash(ctx, tx.Hash())
+	return err == nil
+}
+
+func isKnownTxError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, known := range knownTxErrors {
+		if strings.Contains(msg, known) {
+			return true
+		}
+	}
+
+	return false
+}
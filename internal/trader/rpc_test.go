@@ -0,0 +1,104 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSender struct {
+	delay time.Duration
+	err   error
+	known map[common.Hash]bool
+}
+
+func (s fakeSender) TransactionByHash(_ context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	if !s.known[hash] {
+		return nil, false, ethereum.NotFound
+	}
+
+	return nil, false, nil
+}
+
+func (s fakeSender) SendTransaction(ctx context.Context, _ *types.Transaction) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(s.delay):
+		return s.err
+	}
+}
+
+func TestMultiSender(t *testing.T) {
+	tx := types.NewTx(&types.DynamicFeeTx{})
+
+	sender := NewMultiSender(
+		[]string{"slow", "known", "rejected"},
+		[]TransactionSender{
+			fakeSender{delay: time.Minute},
+			fakeSender{err: errors.New("already known")},
+			fakeSender{err: errors.New("insufficient funds")},
+		},
+	)
+	require.NoError(t, sender.SendTransaction(context.Background(), tx))
+
+	sender = NewMultiSender(
+		[]string{"underpriced", "rejected"},
+		[]TransactionSender{
+			fakeSender{err: errors.New("replacement transaction underpriced")},
+			fakeSender{err: errors.New("insufficient funds")},
+		},
+	)
+	err := sender.SendTransaction(context.Background(), tx)
+	require.ErrorContains(t, err, "underpriced")
+	require.ErrorContains(t, err, "insufficient funds")
+
+	sender = NewMultiSender(
+		[]string{"replaced"},
+		[]TransactionSender{fakeSender{err: errors.New("nonce too low")}},
+	)
+	require.ErrorContains(t, sender.SendTransaction(context.Background(), tx), "nonce too low")
+
+	sender = NewMultiSender(
+		[]string{"mined"},
+		[]TransactionSender{fakeSender{err: errors.New("nonce too low"), known: map[common.Hash]bool{tx.Hash(): true}}},
+	)
+	require.NoError(t, sender.SendTransaction(context.Background(), tx))
+}
+
+func newTestNode(blockNumber int, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		time.Sleep(delay)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":1,"result":"0x%x"}`, blockNumber)
+	}))
+}
+
+func TestDialFastest(t *testing.T) {
+	slow := newTestNode(1, 200*time.Millisecond)
+	defer slow.Close()
+	fast := newTestNode(2, 0)
+	defer fast.Close()
+	broken := httptest.NewServer(http.NotFoundHandler())
+	defer broken.Close()
+
+	client, err := DialFastest(context.Background(), []string{slow.URL, broken.URL, fast.URL})
+	require.NoError(t, err)
+	defer client.Close()
+
+	blockNumber, err := client.BlockNumber(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), blockNumber)
+
+	_, err = DialFastest(context.Background(), []string{broken.URL})
+	require.Error(t, err)
+}
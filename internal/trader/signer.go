@@ -0,0 +1,79 @@
+package trader
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+// KeystoreSigner signs with the account private key when it is configured,
+// otherwise with the keystore entry unlocked by the account passphrase.
+type KeystoreSigner struct {
+	keystore *keystore.KeyStore
+	chainID  *big.Int
+}
+
+func NewKeystoreSigner(keystore *keystore.KeyStore, chainID *big.Int) *KeystoreSigner {
+	return &KeystoreSigner{
+		keystore: keystore,
+		chainID:  chainID,
+	}
+}
+
+func (s *KeystoreSigner) Address(account config.Account) (common.Address, error) {
+	if account.PrivKey == "" {
+		return common.HexToAddress(account.Address), nil
+	}
+
+	priv, err := crypto.HexToECDSA(account.PrivKey)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("invalid private key: %w", err)
+	}
+
+	return crypto.PubkeyToAddress(priv.PublicKey), nil
+}
+
+func (s *KeystoreSigner) SignTx(account config.Account, tx *types.Transaction) (*types.Transaction, error) {
+	if account.PrivKey != "" {
+		priv, err := crypto.HexToECDSA(account.PrivKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid private key: %w", err)
+		}
+
+		return types.SignTx(tx, types.LatestSignerForChainID(s.chainID), priv)
+	}
+
+	return s.keystore.SignTxWithPassphrase(
+		accounts.Account{Address: common.HexToAddress(account.Address)}, account.Passphrase, tx, s.chainID)
+}
+
+// SignHash signs a digest, such as an EIP-712 hash, and returns the
+// signature with a recovery id of 27 or 28 as expected by contracts.
+func (s *KeystoreSigner) SignHash(account config.Account, hash common.Hash) ([]byte, error) {
+	var sig []byte
+	var err error
+	if account.PrivKey != "" {
+		priv, keyErr := crypto.HexToECDSA(account.PrivKey)
+		if keyErr != nil {
+			return nil, fmt.Errorf("invalid private key: %w", keyErr)
+		}
+
+		sig, err = crypto.Sign(hash.Bytes(), priv)
+	} else {
+		sig, err = s.keystore.SignHashWithPassphrase(
+			accounts.Account{Address: common.HexToAddress(account.Address)}, account.Passphrase, hash.Bytes())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
@@ -0,0 +1,505 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/gasprice"
+)
+
+const (
+	gasMultiplierBPS    = 12_000 // 1.2
+	gweiDecimals        = 9
+	maxGasLimit         = 20_000_000
+	defaultDeadlineTime = 24 * time.Second
+	tradeTimeout        = 30 * time.Second
+	deadlineGracePeriod = 15 * time.Second // for the block including a swap at its deadline to be seen
+	defaultSwapDeadline = 24 * time.Hour   // for routers requiring a deadline when none is configured
+
+	minReplacementBumpPercent = 10
+	defaultMaxReplacements    = 3
+	replaceSlackBlocks        = 2 // blocks waited for the last replacement past its replace_after_blocks
+)
+
+// errPresignGasLimit is returned when presigning without gas_limit, as the
+// swap can not be estimated before the sale opens.
+var errPresignGasLimit = errors.New("presign requires gas_limit")
+
+// errPresignDeadline is returned when presigning swaps with a deadline that
+// start on a block or an on-chain event, as the deadline would be counted
+// from signing rather than from when the sale opens.
+var errPresignDeadline = errors.New("deadline_seconds with presign requires start_time, not a chain trigger")
+
+// errExactOutputMaxPrice is returned when max_price is set along with an
+// exact output account, whose swap is not quoted. Its max_input_amount
+// already bounds the price.
+var errExactOutputMaxPrice = errors.New("max_price is not supported with exact_output, bound the price with max_input_amount")
+
+// ChainClient is the subset of ethclient.Client used to make trades.
+type ChainClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+}
+
+// Signer resolves the sender address of an account and signs its
+// transactions and permits.
+type Signer interface {
+	Address(account config.Account) (common.Address, error)
+	SignTx(account config.Account, tx *types.Transaction) (*types.Transaction, error)
+	SignHash(account config.Account, hash common.Hash) ([]byte, error)
+}
+
+// CalldataBuilder builds the router call for a swap.
+type CalldataBuilder interface {
+	BuildSwap(ctx context.Context, params SwapParams) (Call, error)
+}
+
+type SwapParams struct {
+	Nonce        uint64
+	GasPrice     *big.Int // max fee per gas suggested by the gas pricer
+	From         common.Address
+	Recipient    common.Address
+	InputToken   common.Address
+	OutputToken  common.Address
+	AmountIn     *big.Int
+	MinAmountOut *big.Int
+
+	// ExactOutput buys exactly AmountOut while spending at most AmountIn.
+	ExactOutput bool
+	AmountOut   *big.Int
+
+	// Deadline is when the swap must revert if still pending, zero if none.
+	Deadline time.Time
+
+	// Permit is set along with its signature when the input token must be
+	// pulled through Permit2 and the router has no valid allowance yet.
+	Permit          *blockchain.PermitSingle
+	PermitSignature []byte
+}
+
+type Call struct {
+	To    common.Address
+	Data  []byte
+	Value *big.Int
+}
+
+type Result struct {
+	Account config.Account
+	Address common.Address
+	TxHash  common.Hash // hash of the mined transaction, or the last one sent
+	Receipt *types.Receipt
+	Err     error
+
+	// TxHashes contains the hashes of the transaction and all its replacements.
+	TxHashes []common.Hash
+
+	// SendDelay is how long after the start time the transaction was sent.
+	SendDelay time.Duration
+
+	// Deadline is the swap deadline, zero if none.
+	Deadline time.Time
+
+	// AmountIn and AmountOut are the amounts swapped according to the
+	// receipt logs, or the amount returned by the simulated swap in dry run
+	// mode. Price is the effective price in input token per output token.
+	AmountIn  *big.Int
+	AmountOut *big.Int
+	Price     float64
+}
+
+type Trader struct {
+	cfg         config.Config
+	chainID     *big.Int
+	gasLimit    uint64
+	client      ChainClient
+	gasPricer   gasprice.GasPricer
+	signer      Signer
+	builder     CalldataBuilder
+	trigger     Trigger
+	broadcaster Broadcaster
+	caller      PendingCaller
+	quoter      Quoter
+
+	tierGasPricers map[string]gasprice.GasPricer
+	legacyTx       bool
+	pollInterval   time.Duration
+}
+
+type Option func(*Trader)
+
+// WithTrigger replaces the default start_time trigger.
+func WithTrigger(trigger Trigger) Option {
+	return func(t *Trader) {
+		t.trigger = trigger
+	}
+}
+
+// WithBroadcaster replaces the default broadcaster, which sends transactions
+// through the chain client.
+func WithBroadcaster(broadcaster Broadcaster) Option {
+	return func(t *Trader) {
+		t.broadcaster = broadcaster
+	}
+}
+
+func New(
+	cfg config.Config,
+	client ChainClient,
+	gasPricer gasprice.GasPricer,
+	signer Signer,
+	builder CalldataBuilder,
+	opts ...Option,
+) *Trader {
+	var gasLimit uint64
+	if cfg.GasLimit > 0 {
+		gasLimit = uint64(cfg.GasLimit)
+		if gasLimit > maxGasLimit {
+			gasLimit = maxGasLimit
+		}
+	}
+
+	t := &Trader{
+		cfg:          cfg,
+		chainID:      big.NewInt(cfg.ChainID),
+		gasLimit:     gasLimit,
+		client:       client,
+		gasPricer:    gasPricer,
+		signer:       signer,
+		builder:      builder,
+		trigger:      NewTimeTrigger(cfg.StartTime),
+		broadcaster:  NewClientBroadcaster(client),
+		pollInterval: time.Second,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Run makes one trade per account concurrently and returns the results in the
+// same order as the accounts. Transactions are built and signed before the
+// trigger fires when presign is enabled, so only the broadcast happens after
+// the sale opens; otherwise everything happens after the trigger fires.
+func (t *Trader) Run(ctx context.Context) ([]Result, error) {
+	if t.cfg.MaxPrice > 0 && slices.ContainsFunc(t.cfg.Accounts, isExactOutput) {
+		return nil, errExactOutputMaxPrice
+	}
+
+	if t.caller != nil {
+		return t.dryRun(ctx)
+	}
+
+	if t.cfg.Presign && t.gasLimit == 0 {
+		return nil, errPresignGasLimit
+	}
+	if t.cfg.Presign && t.cfg.HasChainTrigger() && slices.ContainsFunc(t.cfg.Accounts, t.hasDeadline) {
+		return nil, errPresignDeadline
+	}
+
+	// Approvals are sent ahead of the trigger so that only swaps remain
+	// once the sale opens.
+	results := make([]Result, len(t.cfg.Accounts))
+	minNonces := make([]uint64, len(t.cfg.Accounts))
+	t.forEachAccount(func(i int, acc config.Account) {
+		results[i].Account = acc
+		minNonces[i], results[i].Err = t.ensureAllowance(ctx, acc)
+	})
+
+	var startTime time.Time
+	var err error
+	if !t.cfg.Presign {
+		if startTime, err = t.trigger.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	txs := make([]*types.Transaction, len(t.cfg.Accounts))
+	t.forEachAccount(func(i int, acc config.Account) {
+		if results[i].Err != nil {
+			results[i].Address, _ = t.signer.Address(acc)
+			return
+		}
+
+		results[i].Deadline = t.swapDeadline(acc)
+		results[i].Address, txs[i], results[i].Err = t.prepare(ctx, acc, minNonces[i], results[i].Deadline)
+	})
+
+	if t.cfg.Presign {
+		log.Printf("Prepared %d transactions", len(txs))
+		if startTime, err = t.trigger.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	sendErrs := t.broadcast(ctx, results, txs, startTime)
+	t.forEachAccount(func(i int, _ config.Account) {
+		if results[i].Err != nil {
+			return
+		}
+
+		results[i].Err = t.confirm(ctx, &results[i], txs[i], sendErrs[i])
+	})
+	t.fillPrices(ctx, results)
+
+	return results, joinResultErrors(results)
+}
+
+func joinResultErrors(results []Result) error {
+	var errs []error
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("account %v: %w", r.Address, r.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (t *Trader) forEachAccount(fn func(i int, acc config.Account)) {
+	var wg sync.WaitGroup
+	for i, acc := range t.cfg.Accounts {
+		wg.Add(1)
+		go func(i int, acc config.Account) {
+			defer wg.Done()
+			fn(i, acc)
+		}(i, acc)
+	}
+	wg.Wait()
+}
+
+// prepare builds and signs the swap transaction of an account. The nonce is
+// at least minNonce, so the swap comes after a freshly sent approve even if
+// the node does not count it as pending yet.
+func (t *Trader) prepare(
+	ctx context.Context, account config.Account, minNonce uint64, deadline time.Time,
+) (common.Address, *types.Transaction, error) {
+	ctx, cancel := context.WithTimeout(ctx, tradeTimeout)
+	defer cancel()
+
+	accountAddress, err := t.signer.Address(account)
+	if err != nil {
+		log.Printf("Fail to resolve account address: error=%v", err)
+		return common.Address{}, nil, err
+	}
+
+	nonce, err := t.client.PendingNonceAt(ctx, accountAddress)
+	if err != nil {
+		log.Printf("Fail to get nonce: error=%v", err)
+		return accountAddress, nil, err
+	}
+	if nonce < minNonce {
+		nonce = minNonce
+	}
+
+	gasQuote, err := t.gasPricerOf(account).GasPrice(ctx)
+	if err != nil {
+		log.Printf("Fail to get gas price: error=%v", err)
+		return accountAddress, nil, err
+	}
+
+	params := SwapParams{
+		Nonce:       nonce,
+		GasPrice:    gasQuote.MaxFee,
+		From:        accountAddress,
+		Recipient:   recipientOf(account, accountAddress),
+		InputToken:  common.HexToAddress(t.cfg.InputToken),
+		OutputToken: common.HexToAddress(t.cfg.OutputToken),
+		AmountIn:    account.InputAmount,
+		Deadline:    deadline,
+	}
+	if account.ExactOutput {
+		if account.MaxInputAmount == nil {
+			return accountAddress, nil, errors.New("max_input_amount is required for exact output")
+		}
+		params.ExactOutput = true
+		params.AmountOut = account.InputAmount
+		params.AmountIn = account.MaxInputAmount
+	} else {
+		params.MinAmountOut, err = t.minReturnAmount(ctx, account, params)
+		if err != nil {
+			log.Printf("Fail to get min return amount: error=%v", err)
+			return accountAddress, nil, err
+		}
+	}
+
+	params.Permit, params.PermitSignature, err = t.signPermit(ctx, account, params)
+	if err != nil {
+		log.Printf("Fail to sign permit: error=%v", err)
+		return accountAddress, nil, err
+	}
+
+	call, err := t.builder.BuildSwap(ctx, params)
+	if err != nil {
+		log.Println("Fail to encode swap:", err)
+		return accountAddress, nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		From:  accountAddress,
+		To:    &call.To,
+		Data:  call.Data,
+		Value: call.Value,
+	}
+
+	gasLimit := t.gasLimit
+	if gasLimit == 0 {
+		gasLimit, err = t.client.EstimateGas(ctx, msg)
+		if err != nil {
+			log.Printf("Fail to estimate gas: from=%v to=%v data=%s error=%v",
+				msg.From, msg.To, hexutil.Encode(msg.Data), err)
+			return accountAddress, nil, err
+		}
+
+		gasLimit = gasLimit * gasMultiplierBPS / 10_000
+	}
+
+	maxGasPrice, gasTipCap := t.gasFees(gasLimit, gasQuote, account.MaxGasFee)
+	log.Printf("Price gas: account=%v source=%s ageMs=%d maxFeePerGas=%v maxPriorityFeePerGas=%v",
+		accountAddress, gasQuote.Source, time.Since(gasQuote.Timestamp).Milliseconds(), maxGasPrice, gasTipCap)
+
+	tx := t.newTx(nonce, maxGasPrice, gasTipCap, gasLimit, msg.To, msg.Data, msg.Value)
+	signedTx, err := t.signer.SignTx(account, tx)
+	if err != nil {
+		log.Printf("Fail to sign transaction: type=%d nonce=%d gas=%d to=%v data=%s error=%v",
+			tx.Type(), tx.Nonce(), tx.Gas(), tx.To(), hexutil.Encode(tx.Data()), err)
+		return accountAddress, nil, err
+	}
+
+	return accountAddress, signedTx, nil
+}
+
+// broadcast sends the prepared transactions of all accounts at once and
+// returns the send error of each account.
+func (t *Trader) broadcast(
+	ctx context.Context, results []Result, txs []*types.Transaction, startTime time.Time,
+) []error {
+	ctx, cancel := context.WithTimeout(ctx, tradeTimeout)
+	defer cancel()
+
+	var indexes []int
+	var prepared []*types.Transaction
+	for i := range results {
+		if results[i].Err == nil {
+			indexes = append(indexes, i)
+			prepared = append(prepared, txs[i])
+		}
+	}
+
+	sent := t.broadcaster.SendTransactions(ctx, prepared)
+
+	sendErrs := make([]error, len(results))
+	for k, i := range indexes {
+		if !sent[k].SentAt.IsZero() {
+			results[i].SendDelay = sent[k].SentAt.Sub(startTime)
+		}
+		sendErrs[i] = sent[k].Err
+	}
+
+	return sendErrs
+}
+
+// confirm logs the result of sending a transaction and waits for it, or one
+// of its replacements, to be mined unless checking the transaction status is
+// skipped.
+func (t *Trader) confirm(ctx context.Context, res *Result, signedTx *types.Transaction, sendErr error) error {
+	res.TxHash = signedTx.Hash()
+	res.TxHashes = []common.Hash{signedTx.Hash()}
+	if sendErr != nil {
+		log.Printf("Fail to submit transaction: sender=%v error=%v", res.Address, sendErr)
+		return sendErr
+	}
+
+	log.Printf("Successfully submit transaction: inputAmount=%v transactionHash=%v sendDelayMs=%d",
+		res.Account.InputAmount, signedTx.Hash(), res.SendDelay.Milliseconds())
+
+	if t.cfg.SkipCheckTxStatus {
+		return nil
+	}
+
+	// Wait for transaction to be mined
+	if err := t.waitMined(ctx, res, signedTx); err != nil {
+		log.Printf("Fail to get transaction receipt: transactionHashes=%v error=%v", res.TxHashes, err)
+		return err
+	}
+
+	if res.Receipt.Status != types.ReceiptStatusSuccessful {
+		log.Printf("Transaction failed: transactionHash=%v status=%v", res.TxHash, res.Receipt.Status)
+		return errors.New("transaction failed")
+	}
+
+	log.Printf("Transaction success: hash=%v", res.TxHash)
+	t.decodeFill(res)
+
+	return nil
+}
+
+// swapDeadline returns the deadline of the swap of an account, counted from
+// start_time when presigning ahead of it, or zero when no deadline is set.
+func (t *Trader) swapDeadline(account config.Account) time.Time {
+	seconds := t.deadlineSeconds(account)
+	if seconds <= 0 {
+		return time.Time{}
+	}
+
+	start := time.Now()
+	if t.cfg.Presign && t.cfg.StartTime.After(start) {
+		start = t.cfg.StartTime
+	}
+
+	return start.Add(time.Duration(seconds) * time.Second)
+}
+
+func (t *Trader) deadlineSeconds(account config.Account) int64 {
+	if account.DeadlineSeconds > 0 {
+		return account.DeadlineSeconds
+	}
+
+	return t.cfg.DeadlineSeconds
+}
+
+func (t *Trader) hasDeadline(account config.Account) bool {
+	return t.deadlineSeconds(account) > 0
+}
+
+func isExactOutput(account config.Account) bool {
+	return account.ExactOutput
+}
+
+// maxInputAmount returns the most an account may spend.
+func maxInputAmount(account config.Account) *big.Int {
+	if account.ExactOutput {
+		return account.MaxInputAmount
+	}
+
+	return account.InputAmount
+}
+
+func recipientOf(account config.Account, accountAddress common.Address) common.Address {
+	if account.Recipient != "" {
+		return common.HexToAddress(account.Recipient)
+	}
+
+	return accountAddress
+}
+
+func callMsg(to common.Address, data []byte) ethereum.CallMsg {
+	return ethereum.CallMsg{To: &to, Data: data}
+}
@@ -0,0 +1,408 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/config"
+	"github.com/hiepnv90/ilo/internal/gasprice"
+)
+
+const testPrivKey = "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+
+type fakeChainClient struct {
+	mu          sync.Mutex
+	nonces      map[common.Address]uint64
+	sent        []*types.Transaction
+	blockNumber uint64
+	blockTime   time.Duration // when set, blocks advance with time instead of per call
+	startTime   time.Time
+	minTipCap   *big.Int // transactions with lower tip are never mined
+	callResult  []byte
+	callResults map[common.Address][]byte // by contract, overrides callResult
+	logs        []*types.Log
+}
+
+func newFakeChainClient() *fakeChainClient {
+	return &fakeChainClient{nonces: make(map[common.Address]uint64)}
+}
+
+func (c *fakeChainClient) BlockNumber(context.Context) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.blockTime > 0 {
+		if c.startTime.IsZero() {
+			c.startTime = time.Now()
+		}
+		return uint64(time.Since(c.startTime) / c.blockTime), nil
+	}
+
+	c.blockNumber++
+	return c.blockNumber, nil
+}
+
+func (c *fakeChainClient) CallContract(_ context.Context, msg ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	if res, ok := c.callResults[*msg.To]; ok {
+		return res, nil
+	}
+
+	return c.callResult, nil
+}
+
+func (c *fakeChainClient) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 100_000, nil
+}
+
+func (c *fakeChainClient) PendingNonceAt(_ context.Context, account common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.nonces[account], nil
+}
+
+func (c *fakeChainClient) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, tx)
+	return nil
+}
+
+func (c *fakeChainClient) TransactionReceipt(_ context.Context, txHash common.Hash) (*types.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tx := range c.sent {
+		if tx.Hash() == txHash && (c.minTipCap == nil || tx.GasTipCap().Cmp(c.minTipCap) >= 0) {
+			return &types.Receipt{
+				Status:            types.ReceiptStatusSuccessful,
+				TxHash:            txHash,
+				Logs:              c.logs,
+				GasUsed:           100_000,
+				EffectiveGasPrice: big.NewInt(10_000_000_000),
+			}, nil
+		}
+	}
+
+	return nil, ethereum.NotFound
+}
+
+type fakeGasPricer struct {
+	maxFee int64
+	tipCap int64
+}
+
+func (p fakeGasPricer) GasPrice(context.Context) (gasprice.GasQuote, error) {
+	return gasprice.GasQuote{
+		MaxFee:    big.NewInt(p.maxFee),
+		TipCap:    big.NewInt(p.tipCap),
+		Source:    "fake",
+		Timestamp: time.Now(),
+	}, nil
+}
+
+func testConfig() config.Config {
+	return config.Config{
+		ChainID:          1,
+		RouterAddress:    "0x68b3465833fb72a70ecdf485e0e4c7bd8665fc45",
+		InputToken:       "0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee",
+		OutputToken:      "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+		FeeTier:          500,
+		GasTipMultiplier: 1.0,
+		Weth:             "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		Accounts: []config.Account{
+			{PrivKey: testPrivKey, InputAmount: big.NewInt(1e18)},
+		},
+	}
+}
+
+func newTestTrader(cfg config.Config, client ChainClient) *Trader {
+	return New(
+		cfg,
+		client,
+		fakeGasPricer{maxFee: 20e9, tipCap: 2e9},
+		NewKeystoreSigner(nil, big.NewInt(cfg.ChainID)),
+		NewUniswapV3Builder(
+			common.HexToAddress(cfg.RouterAddress),
+			common.HexToAddress(cfg.Weth),
+			DirectRoute(big.NewInt(int64(cfg.FeeTier))),
+		),
+	)
+}
+
+func TestTraderRun(t *testing.T) {
+	cfg := testConfig()
+	client := newFakeChainClient()
+
+	results, err := newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Len(t, client.sent, 1)
+
+	tx := client.sent[0]
+	require.Equal(t, results[0].TxHash, tx.Hash())
+	require.Equal(t, common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"), results[0].Address)
+	require.Equal(t, uint64(120_000), tx.Gas())
+	require.Equal(t, big.NewInt(1e18), tx.Value())
+	require.Equal(t, big.NewInt(20_000_000_000), tx.GasFeeCap())
+	require.Equal(t, big.NewInt(2_000_000_000), tx.GasTipCap())
+	require.NotNil(t, results[0].Receipt)
+}
+
+func TestGasPriceWithCap(t *testing.T) {
+	quote := gasprice.GasQuote{MaxFee: big.NewInt(1e9), TipCap: big.NewInt(1e9)}
+	maxGasPrice, gasTipCap := gasPriceWithCap(100_000, quote, 2, nil)
+	require.Equal(t, big.NewInt(2_000_000_000), maxGasPrice)
+	require.Equal(t, big.NewInt(2_000_000_000), gasTipCap)
+
+	maxGasPrice, gasTipCap = gasPriceWithCap(100_000, quote, 1.5, nil)
+	require.Equal(t, big.NewInt(1_500_000_000), maxGasPrice)
+	require.Equal(t, big.NewInt(1_500_000_000), gasTipCap)
+
+	quote.MaxFee = big.NewInt(30e9)
+	maxGasPrice, gasTipCap = gasPriceWithCap(100_000, quote, 2, big.NewInt(1e15))
+	require.Equal(t, big.NewInt(10_000_000_000), maxGasPrice)
+	require.Equal(t, big.NewInt(10_000_000_000), gasTipCap)
+}
+
+func TestTraderRunPresign(t *testing.T) {
+	cfg := testConfig()
+	cfg.Presign = true
+	cfg.GasLimit = 300_000
+	cfg.StartTime = time.Now().Add(200 * time.Millisecond)
+	client := newFakeChainClient()
+
+	results, err := newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 1)
+	require.Equal(t, uint64(300_000), client.sent[0].Gas())
+	require.False(t, time.Now().Before(cfg.StartTime))
+	require.GreaterOrEqual(t, results[0].SendDelay, time.Duration(0))
+
+	// Gas can not be estimated before the sale opens.
+	cfg.GasLimit = 0
+	_, err = newTestTrader(cfg, client).Run(context.Background())
+	require.ErrorIs(t, err, errPresignGasLimit)
+	require.Len(t, client.sent, 1)
+
+	// A deadline can only be counted from start_time.
+	cfg.GasLimit = 300_000
+	cfg.StartBlock = 100
+	cfg.Accounts[0].DeadlineSeconds = 60
+	_, err = newTestTrader(cfg, client).Run(context.Background())
+	require.ErrorIs(t, err, errPresignDeadline)
+	require.Len(t, client.sent, 1)
+}
+
+func TestTraderRunReplace(t *testing.T) {
+	cfg := testConfig()
+	cfg.ReplaceAfterBlocks = 1
+	cfg.ReplaceBumpPercent = 20
+	client := newFakeChainClient()
+	client.minTipCap = big.NewInt(2_800_000_000)
+
+	results, err := newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 3)
+	require.Len(t, results[0].TxHashes, 3)
+	require.Equal(t, client.sent[2].Hash(), results[0].TxHash)
+	require.Equal(t, client.sent[0].Nonce(), client.sent[2].Nonce())
+	require.Equal(t, big.NewInt(2_880_000_000), client.sent[2].GasTipCap())
+	require.Equal(t, big.NewInt(28_800_000_000), client.sent[2].GasFeeCap())
+}
+
+func TestTraderRunReplaceSlowBlocks(t *testing.T) {
+	cfg := testConfig()
+	cfg.ReplaceAfterBlocks = 1
+	cfg.ReplaceBumpPercent = 20
+	client := newFakeChainClient()
+	client.blockTime = 100 * time.Millisecond
+	client.minTipCap = big.NewInt(3_400_000_000)
+
+	trader := newTestTrader(cfg, client)
+	trader.pollInterval = 10 * time.Millisecond
+
+	results, err := trader.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 4)
+	require.Equal(t, client.sent[3].Hash(), results[0].TxHash)
+	require.Equal(t, big.NewInt(3_456_000_000), client.sent[3].GasTipCap())
+
+	client = newFakeChainClient()
+	client.blockTime = 100 * time.Millisecond
+	client.minTipCap = big.NewInt(100e9)
+
+	trader = newTestTrader(cfg, client)
+	trader.pollInterval = 10 * time.Millisecond
+
+	_, err = trader.Run(context.Background())
+	require.ErrorContains(t, err, "not mined by block 6")
+	require.Len(t, client.sent, 4)
+}
+
+func TestReplaceMaxGasFee(t *testing.T) {
+	cfg := testConfig()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		GasTipCap: big.NewInt(100),
+		GasFeeCap: big.NewInt(1000),
+		Gas:       100_000,
+	})
+
+	account := cfg.Accounts[0]
+	account.MaxGasFee = big.NewInt(1000 * 100_000)
+	_, err := newTestTrader(cfg, newFakeChainClient()).replace(context.Background(), account, tx)
+	require.ErrorIs(t, err, errMaxGasFeeReached)
+
+	account.MaxGasFee = big.NewInt(1150 * 100_000)
+	replacement, err := newTestTrader(cfg, newFakeChainClient()).replace(context.Background(), account, tx)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1100), replacement.GasFeeCap())
+	require.Equal(t, big.NewInt(110), replacement.GasTipCap())
+}
+
+type fakePendingCaller struct {
+	msgs []ethereum.CallMsg
+}
+
+func (c *fakePendingCaller) PendingCallContract(_ context.Context, msg ethereum.CallMsg) ([]byte, error) {
+	c.msgs = append(c.msgs, msg)
+	return common.BigToHash(big.NewInt(2500_000000)).Bytes(), nil
+}
+
+func TestTraderDryRun(t *testing.T) {
+	cfg := testConfig()
+	cfg.StartTime = time.Now().Add(time.Hour)
+	client := newFakeChainClient()
+	caller := &fakePendingCaller{}
+
+	trader := newTestTrader(cfg, client)
+	WithDryRun(caller)(trader)
+
+	results, err := trader.Run(context.Background())
+	require.NoError(t, err)
+	require.Empty(t, client.sent)
+	require.Len(t, caller.msgs, 1)
+	require.Equal(t, big.NewInt(1e18), caller.msgs[0].Value)
+	require.Equal(t, results[0].Address, caller.msgs[0].From)
+	require.Equal(t, big.NewInt(2500_000000), results[0].AmountOut)
+}
+
+func TestTraderRunExactOutput(t *testing.T) {
+	cfg := testConfig()
+	cfg.Accounts[0].ExactOutput = true
+	cfg.Accounts[0].InputAmount = big.NewInt(2500_000000)
+	client := newFakeChainClient()
+
+	_, err := newTestTrader(cfg, client).Run(context.Background())
+	require.ErrorContains(t, err, "max_input_amount")
+	require.Empty(t, client.sent)
+
+	cfg.Accounts[0].MaxInputAmount = big.NewInt(1e18)
+	_, err = newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 1)
+
+	// ETH is sent up to the max input amount and refunded by the multicall.
+	tx := client.sent[0]
+	require.Equal(t, big.NewInt(1e18), tx.Value())
+	require.Equal(t, common.FromHex("0xac9650d8"), tx.Data()[:4])
+
+	cfg.MaxPrice = 0.0004
+	_, err = newTestTrader(cfg, newFakeChainClient()).Run(context.Background())
+	require.ErrorIs(t, err, errExactOutputMaxPrice)
+}
+
+func TestTraderRunDeadline(t *testing.T) {
+	cfg := testConfig()
+	cfg.DeadlineSeconds = 60
+	client := newFakeChainClient()
+
+	start := time.Now()
+	results, err := newTestTrader(cfg, client).Run(context.Background())
+	require.NoError(t, err)
+	require.WithinDuration(t, start.Add(time.Minute), results[0].Deadline, 5*time.Second)
+	require.Equal(t, common.FromHex("0x5ae401dc"), client.sent[0].Data()[:4]) // multicall(uint256,bytes[])
+}
+
+func TestSwapDeadline(t *testing.T) {
+	cfg := testConfig()
+	trader := newTestTrader(cfg, newFakeChainClient())
+	require.True(t, trader.swapDeadline(cfg.Accounts[0]).IsZero())
+
+	trader.cfg.DeadlineSeconds = 60
+	account := cfg.Accounts[0]
+	account.DeadlineSeconds = 30
+	require.WithinDuration(t, time.Now().Add(30*time.Second), trader.swapDeadline(account), time.Second)
+
+	// Presigned swaps count from the start time.
+	trader.cfg.Presign = true
+	trader.cfg.StartTime = time.Now().Add(time.Hour)
+	require.WithinDuration(t, trader.cfg.StartTime.Add(30*time.Second), trader.swapDeadline(account), time.Second)
+}
+
+func TestTraderRunGasTier(t *testing.T) {
+	cfg := testConfig()
+	cfg.Accounts[0].GasTier = "low"
+	client := newFakeChainClient()
+
+	trader := newTestTrader(cfg, client)
+	WithTierGasPricers(map[string]gasprice.GasPricer{
+		"low": fakeGasPricer{maxFee: 10e9, tipCap: 1e9},
+	})(trader)
+
+	_, err := trader.Run(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10e9), client.sent[0].GasFeeCap())
+	require.Equal(t, big.NewInt(1e9), client.sent[0].GasTipCap())
+}
+
+func TestTraderRunLegacyTx(t *testing.T) {
+	cfg := testConfig()
+	cfg.Accounts[0].MaxGasFee = big.NewInt(1e15)
+	client := newFakeChainClient()
+
+	trader := newTestTrader(cfg, client)
+	WithLegacyTx()(trader)
+
+	_, err := trader.Run(context.Background())
+	require.NoError(t, err)
+	require.Len(t, client.sent, 1)
+
+	tx := client.sent[0]
+	require.Equal(t, uint8(types.LegacyTxType), tx.Type())
+	require.Equal(t, new(big.Int).Div(big.NewInt(1e15), new(big.Int).SetUint64(tx.Gas())), tx.GasPrice())
+	require.Equal(t, big.NewInt(cfg.ChainID), tx.ChainId())
+}
+
+func TestLegacyGasPrice(t *testing.T) {
+	quote := gasprice.GasQuote{BaseFee: big.NewInt(10e9), MaxFee: big.NewInt(25e9), GasPrice: big.NewInt(12e9)}
+	require.Equal(t, big.NewInt(13e9), legacyGasPrice(quote, 1.5))
+	require.Equal(t, big.NewInt(12e9), legacyGasPrice(quote, 1.0))
+
+	// Without a base fee, the whole gas price is the tip.
+	quote = gasprice.GasQuote{MaxFee: big.NewInt(10e9)}
+	require.Equal(t, big.NewInt(20e9), legacyGasPrice(quote, 2.0))
+}
+
+type fakeHeaderClient struct {
+	baseFee *big.Int
+}
+
+func (c fakeHeaderClient) HeaderByNumber(context.Context, *big.Int) (*types.Header, error) {
+	return &types.Header{BaseFee: c.baseFee}, nil
+}
+
+func TestSupportsDynamicFeeTx(t *testing.T) {
+	dynamic, err := SupportsDynamicFeeTx(context.Background(), fakeHeaderClient{baseFee: big.NewInt(1e9)})
+	require.NoError(t, err)
+	require.True(t, dynamic)
+
+	dynamic, err = SupportsDynamicFeeTx(context.Background(), fakeHeaderClient{})
+	require.NoError(t, err)
+	require.False(t, dynamic)
+}
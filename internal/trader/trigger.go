@@ -0,0 +1,284 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+	"github.com/hiepnv90/ilo/internal/config"
+)
+
+// Trigger blocks until the sale opens and returns the time it opened, which
+// is used as the reference for send delays.
+type Trigger interface {
+	Wait(ctx context.Context) (time.Time, error)
+}
+
+// SubscribeClient is the subset of ethclient.Client used to watch the chain
+// over a websocket RPC.
+type SubscribeClient interface {
+	BlockNumber(ctx context.Context) (uint64, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (ethereum.Subscription, error)
+	SubscribeFilterLogs(ctx context.Context, q ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+}
+
+// NewChainTrigger returns the block or pool trigger selected in cfg.
+func NewChainTrigger(cfg config.Config, client SubscribeClient) (Trigger, error) {
+	if cfg.StartBlock > 0 {
+		return NewBlockTrigger(client, cfg.StartBlock), nil
+	}
+
+	if cfg.FactoryAddress == "" {
+		return nil, errors.New("factory_address is required to wait for pool events")
+	}
+
+	weth := common.HexToAddress(cfg.Weth)
+	return NewPoolTrigger(
+		client,
+		common.HexToAddress(cfg.FactoryAddress),
+		toTokenAddress(common.HexToAddress(cfg.InputToken), weth),
+		toTokenAddress(common.HexToAddress(cfg.OutputToken), weth),
+		big.NewInt(int64(cfg.FeeTier)),
+		cfg.StartOnLiquidityAdded,
+	), nil
+}
+
+// TimeTrigger fires at a wall-clock time, or immediately if it is zero.
+type TimeTrigger struct {
+	startTime time.Time
+}
+
+func NewTimeTrigger(startTime time.Time) *TimeTrigger {
+	return &TimeTrigger{startTime: startTime}
+}
+
+func (t *TimeTrigger) Wait(ctx context.Context) (time.Time, error) {
+	if err := waitUntil(ctx, t.startTime); err != nil {
+		return time.Time{}, err
+	}
+
+	if t.startTime.IsZero() {
+		return time.Now(), nil
+	}
+
+	return t.startTime, nil
+}
+
+func waitUntil(ctx context.Context, startTime time.Time) error {
+	delay := time.Until(startTime)
+	if delay <= 0 {
+		return nil
+	}
+
+	log.Printf("Wait %v before starting to make trades\n", delay)
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// BlockTrigger fires once the block right before startBlock is mined, so the
+// trades can be included in startBlock.
+type BlockTrigger struct {
+	client     SubscribeClient
+	startBlock uint64
+}
+
+func NewBlockTrigger(client SubscribeClient, startBlock uint64) *BlockTrigger {
+	return &BlockTrigger{
+		client:     client,
+		startBlock: startBlock,
+	}
+}
+
+func (t *BlockTrigger) Wait(ctx context.Context) (time.Time, error) {
+	headers := make(chan *types.Header, 16)
+	sub, err := t.client.SubscribeNewHead(ctx, headers)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("subscribe new head: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	blockNumber, err := t.client.BlockNumber(ctx)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("get block number: %w", err)
+	}
+
+	log.Printf("Wait for block %d: currentBlock=%d", t.startBlock, blockNumber)
+	for blockNumber+1 < t.startBlock {
+		select {
+		case <-ctx.Done():
+			return time.Time{}, ctx.Err()
+		case err = <-sub.Err():
+			return time.Time{}, fmt.Errorf("new head subscription: %w", err)
+		case header := <-headers:
+			blockNumber = header.Number.Uint64()
+		}
+	}
+
+	log.Printf("Block %d is mined, start making trades", blockNumber)
+	return time.Now(), nil
+}
+
+// PoolTrigger fires when the uniswap v3 pool of the sale is created or, if
+// waitLiquidity is set, when liquidity is added to it.
+type PoolTrigger struct {
+	client        SubscribeClient
+	factory       common.Address
+	token0        common.Address
+	token1        common.Address
+	fee           *big.Int
+	waitLiquidity bool
+}
+
+func NewPoolTrigger(
+	client SubscribeClient,
+	factory common.Address,
+	tokenA common.Address,
+	tokenB common.Address,
+	fee *big.Int,
+	waitLiquidity bool,
+) *PoolTrigger {
+	token0, token1 := blockchain.SortTokens(tokenA, tokenB)
+	return &PoolTrigger{
+		client:        client,
+		factory:       factory,
+		token0:        token0,
+		token1:        token1,
+		fee:           fee,
+		waitLiquidity: waitLiquidity,
+	}
+}
+
+func (t *PoolTrigger) Wait(ctx context.Context) (time.Time, error) {
+	pool, err := t.waitForPool(ctx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if t.waitLiquidity {
+		if err = t.waitForLiquidity(ctx, pool); err != nil {
+			return time.Time{}, err
+		}
+	}
+
+	return time.Now(), nil
+}
+
+func (t *PoolTrigger) waitForPool(ctx context.Context) (common.Address, error) {
+	logs := make(chan types.Log, 16)
+	sub, err := t.client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{t.factory},
+		Topics: [][]common.Hash{
+			{blockchain.PoolCreatedTopic()},
+			{common.BytesToHash(t.token0.Bytes())},
+			{common.BytesToHash(t.token1.Bytes())},
+			{common.BigToHash(t.fee)},
+		},
+	}, logs)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("subscribe pool created: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	pool, err := t.getPool(ctx)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if pool != (common.Address{}) {
+		log.Printf("Pool already created: pool=%v", pool)
+		return pool, nil
+	}
+
+	log.Printf("Wait for pool creation: token0=%v token1=%v fee=%v", t.token0, t.token1, t.fee)
+	select {
+	case <-ctx.Done():
+		return common.Address{}, ctx.Err()
+	case err = <-sub.Err():
+		return common.Address{}, fmt.Errorf("pool created subscription: %w", err)
+	case l := <-logs:
+		pool, err = blockchain.DecodePoolCreated(l)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("decode pool created: %w", err)
+		}
+
+		log.Printf("Pool created: pool=%v block=%d", pool, l.BlockNumber)
+		return pool, nil
+	}
+}
+
+func (t *PoolTrigger) waitForLiquidity(ctx context.Context, pool common.Address) error {
+	logs := make(chan types.Log, 16)
+	sub, err := t.client.SubscribeFilterLogs(ctx, ethereum.FilterQuery{
+		Addresses: []common.Address{pool},
+		Topics:    [][]common.Hash{{blockchain.MintTopic()}},
+	}, logs)
+	if err != nil {
+		return fmt.Errorf("subscribe mint: %w", err)
+	}
+	defer sub.Unsubscribe()
+
+	// Pools are usually created and funded in the same transaction, so the
+	// liquidity may already be there.
+	liquidity, err := t.getLiquidity(ctx, pool)
+	if err != nil {
+		return err
+	}
+	if liquidity.Sign() > 0 {
+		log.Printf("Pool already has liquidity: pool=%v liquidity=%v", pool, liquidity)
+		return nil
+	}
+
+	log.Printf("Wait for liquidity: pool=%v", pool)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err = <-sub.Err():
+		return fmt.Errorf("mint subscription: %w", err)
+	case l := <-logs:
+		log.Printf("Liquidity added: pool=%v block=%d", pool, l.BlockNumber)
+		return nil
+	}
+}
+
+func (t *PoolTrigger) getPool(ctx context.Context) (common.Address, error) {
+	data, err := blockchain.EncodeGetPool(t.token0, t.token1, t.fee)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("encode get pool: %w", err)
+	}
+
+	res, err := t.client.CallContract(ctx, ethereum.CallMsg{To: &t.factory, Data: data}, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("call get pool: %w", err)
+	}
+
+	return blockchain.DecodeGetPool(res)
+}
+
+func (t *PoolTrigger) getLiquidity(ctx context.Context, pool common.Address) (*big.Int, error) {
+	data, err := blockchain.EncodeLiquidity()
+	if err != nil {
+		return nil, fmt.Errorf("encode liquidity: %w", err)
+	}
+
+	res, err := t.client.CallContract(ctx, ethereum.CallMsg{To: &pool, Data: data}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("call liquidity: %w", err)
+	}
+
+	return blockchain.DecodeLiquidity(res)
+}
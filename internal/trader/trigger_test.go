@@ -0,0 +1,119 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+type fakeSubscription struct {
+	errCh chan error
+}
+
+func (s fakeSubscription) Unsubscribe() {}
+
+func (s fakeSubscription) Err() <-chan error {
+	return s.errCh
+}
+
+type fakeSubscribeClient struct {
+	blockNumber uint64
+	headers     []*types.Header
+	logs        map[common.Address][]types.Log
+	calls       map[common.Address][]byte
+}
+
+func (c *fakeSubscribeClient) BlockNumber(context.Context) (uint64, error) {
+	return c.blockNumber, nil
+}
+
+func (c *fakeSubscribeClient) CallContract(
+	_ context.Context, msg ethereum.CallMsg, _ *big.Int,
+) ([]byte, error) {
+	return c.calls[*msg.To], nil
+}
+
+func (c *fakeSubscribeClient) SubscribeNewHead(
+	_ context.Context, ch chan<- *types.Header,
+) (ethereum.Subscription, error) {
+	for _, h := range c.headers {
+		ch <- h
+	}
+
+	return fakeSubscription{errCh: make(chan error)}, nil
+}
+
+func (c *fakeSubscribeClient) SubscribeFilterLogs(
+	_ context.Context, q ethereum.FilterQuery, ch chan<- types.Log,
+) (ethereum.Subscription, error) {
+	for _, l := range c.logs[q.Addresses[0]] {
+		ch <- l
+	}
+
+	return fakeSubscription{errCh: make(chan error)}, nil
+}
+
+func TestBlockTrigger(t *testing.T) {
+	client := &fakeSubscribeClient{
+		blockNumber: 98,
+		headers: []*types.Header{
+			{Number: big.NewInt(99)},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := NewBlockTrigger(client, 100).Wait(ctx)
+	require.NoError(t, err)
+
+	_, err = NewBlockTrigger(client, 101).Wait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPoolTrigger(t *testing.T) {
+	factory := common.HexToAddress("0x1F98431c8aD98523631AE4a59f267346ea31F984")
+	pool := common.HexToAddress("0x88e6A0c2dDD26FEEb64F039a2c41296FcB3f5640")
+	weth := common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	usdc := common.HexToAddress("0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48")
+
+	poolCreated := types.Log{
+		Address: factory,
+		Topics:  []common.Hash{blockchain.PoolCreatedTopic()},
+		Data: append(
+			common.BigToHash(big.NewInt(10)).Bytes(),
+			common.BytesToHash(pool.Bytes()).Bytes()...),
+		BlockNumber: 100,
+	}
+	client := &fakeSubscribeClient{
+		logs: map[common.Address][]types.Log{
+			factory: {poolCreated},
+			pool:    {{Address: pool, Topics: []common.Hash{blockchain.MintTopic()}}},
+		},
+		calls: map[common.Address][]byte{
+			factory: common.Hash{}.Bytes(),
+			pool:    common.Hash{}.Bytes(),
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	trigger := NewPoolTrigger(client, factory, weth, usdc, big.NewInt(500), true)
+	require.Equal(t, usdc, trigger.token0)
+
+	_, err := trigger.Wait(ctx)
+	require.NoError(t, err)
+
+	delete(client.logs, pool)
+	_, err = trigger.Wait(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
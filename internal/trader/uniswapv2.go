@@ -0,0 +1,174 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+// V2Path is the tokens a Uniswap V2 style swap goes through. An empty path
+// swaps straight from the input token to the output token.
+type V2Path []common.Address
+
+// ParseV2Path parses a path of token addresses.
+func ParseV2Path(tokens []string) (V2Path, error) {
+	if len(tokens) == 1 {
+		return nil, errors.New("invalid path: at least 2 tokens are required")
+	}
+
+	path := make(V2Path, 0, len(tokens))
+	for _, token := range tokens {
+		if !common.IsHexAddress(token) {
+			return nil, fmt.Errorf("invalid path token: %s", token)
+		}
+		path = append(path, common.HexToAddress(token))
+	}
+
+	return path, nil
+}
+
+// tokens returns the path from the input token to the output token, ETH
+// being swapped as WETH.
+func (p V2Path) tokens(inputToken, outputToken, weth common.Address) ([]common.Address, error) {
+	inputToken, outputToken = toTokenAddress(inputToken, weth), toTokenAddress(outputToken, weth)
+	if len(p) == 0 {
+		return []common.Address{inputToken, outputToken}, nil
+	}
+
+	if p[0] != inputToken || p[len(p)-1] != outputToken {
+		return nil, fmt.Errorf("path %v does not swap %v to %v", []common.Address(p), inputToken, outputToken)
+	}
+
+	return p, nil
+}
+
+// UniswapV2Builder builds swaps for Uniswap V2 style routers. Tokens taking a
+// fee on transfers need the fee on transfer variants, which support exact
+// input swaps only.
+type UniswapV2Builder struct {
+	router        common.Address
+	weth          common.Address
+	path          V2Path
+	feeOnTransfer bool
+}
+
+func NewUniswapV2Builder(router, weth common.Address, path V2Path, feeOnTransfer bool) *UniswapV2Builder {
+	return &UniswapV2Builder{
+		router:        router,
+		weth:          weth,
+		path:          path,
+		feeOnTransfer: feeOnTransfer,
+	}
+}
+
+func (b *UniswapV2Builder) BuildSwap(_ context.Context, params SwapParams) (Call, error) {
+	path, err := b.path.tokens(params.InputToken, params.OutputToken, b.weth)
+	if err != nil {
+		return Call{}, err
+	}
+	if params.ExactOutput && b.feeOnTransfer {
+		return Call{}, errors.New("fee on transfer swaps do not support exact output")
+	}
+
+	deadline := params.Deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(defaultSwapDeadline)
+	}
+
+	var data []byte
+	ethIn, ethOut := isEth(params.InputToken), isEth(params.OutputToken)
+	switch {
+	case params.ExactOutput && ethIn:
+		data, err = blockchain.EncodeSwapETHForExactTokens(params.AmountOut, path, params.Recipient, deadline.Unix())
+	case params.ExactOutput && ethOut:
+		data, err = blockchain.EncodeSwapTokensForExactETH(
+			params.AmountOut, params.AmountIn, path, params.Recipient, deadline.Unix())
+	case params.ExactOutput:
+		data, err = blockchain.EncodeSwapTokensForExactTokens(
+			params.AmountOut, params.AmountIn, path, params.Recipient, deadline.Unix())
+	case ethIn:
+		data, err = blockchain.EncodeSwapExactETHForTokens(
+			params.MinAmountOut, path, params.Recipient, deadline.Unix(), b.feeOnTransfer)
+	case ethOut:
+		data, err = blockchain.EncodeSwapExactTokensForETH(
+			params.AmountIn, params.MinAmountOut, path, params.Recipient, deadline.Unix(), b.feeOnTransfer)
+	default:
+		data, err = blockchain.EncodeSwapExactTokensForTokens(
+			params.AmountIn, params.MinAmountOut, path, params.Recipient, deadline.Unix(), b.feeOnTransfer)
+	}
+	if err != nil {
+		return Call{}, err
+	}
+
+	call := Call{To: b.router, Data: data}
+	if ethIn {
+		call.Value = params.AmountIn
+	}
+
+	return call, nil
+}
+
+// DecodeAmountOut decodes the last amount returned by the swap. Fee on
+// transfer swaps return nothing, so their amount out is unknown.
+func (b *UniswapV2Builder) DecodeAmountOut(data []byte) (*big.Int, error) {
+	if b.feeOnTransfer {
+		return nil, nil
+	}
+
+	return lastAmount(data)
+}
+
+// UniswapV2Quoter quotes swaps with the getAmountsOut of a Uniswap V2 style
+// router.
+type UniswapV2Quoter struct {
+	caller ContractCaller
+	router common.Address
+	weth   common.Address
+	path   V2Path
+}
+
+func NewUniswapV2Quoter(caller ContractCaller, router, weth common.Address, path V2Path) *UniswapV2Quoter {
+	return &UniswapV2Quoter{
+		caller: caller,
+		router: router,
+		weth:   weth,
+		path:   path,
+	}
+}
+
+func (q *UniswapV2Quoter) QuoteAmountOut(ctx context.Context, params SwapParams) (*big.Int, error) {
+	path, err := q.path.tokens(params.InputToken, params.OutputToken, q.weth)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := blockchain.EncodeGetAmountsOut(params.AmountIn, path)
+	if err != nil {
+		return nil, fmt.Errorf("encode quote: %w", err)
+	}
+
+	res, err := q.caller.CallContract(ctx, callMsg(q.router, data), nil)
+	if err != nil {
+		return nil, fmt.Errorf("call quote: %w", err)
+	}
+
+	return lastAmount(res)
+}
+
+func lastAmount(data []byte) (*big.Int, error) {
+	amounts, err := blockchain.DecodeAmounts(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(amounts) == 0 {
+		return nil, errors.New("no amounts returned")
+	}
+
+	return amounts[len(amounts)-1], nil
+}
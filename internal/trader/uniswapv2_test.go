@@ -0,0 +1,87 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseV2Path(t *testing.T) {
+	path, err := ParseV2Path([]string{
+		"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2",
+		"0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48",
+	})
+	require.NoError(t, err)
+	require.Len(t, path, 2)
+
+	_, err = ParseV2Path([]string{"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"})
+	require.Error(t, err)
+
+	_, err = ParseV2Path([]string{"0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", "usdc"})
+	require.Error(t, err)
+}
+
+func TestUniswapV2Builder(t *testing.T) {
+	router := common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D")
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+
+	params := SwapParams{
+		Recipient:    common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"),
+		InputToken:   ethAddress,
+		OutputToken:  usdc,
+		AmountIn:     big.NewInt(1e18),
+		MinAmountOut: big.NewInt(2500_000000),
+	}
+	call, err := NewUniswapV2Builder(router, weth, nil, false).BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, router, call.To)
+	require.Equal(t, big.NewInt(1e18), call.Value)
+	require.Equal(t, "0x7ff36ab5", hexutil.Encode(call.Data[:4]))
+
+	feeOnTransfer := NewUniswapV2Builder(router, weth, nil, true)
+	call, err = feeOnTransfer.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, "0xb6f9de95", hexutil.Encode(call.Data[:4]))
+
+	amountOut, err := feeOnTransfer.DecodeAmountOut(nil)
+	require.NoError(t, err)
+	require.Nil(t, amountOut)
+
+	params.ExactOutput = true
+	_, err = feeOnTransfer.BuildSwap(context.Background(), params)
+	require.Error(t, err)
+
+	// The path must swap the input token to the output token.
+	params.ExactOutput = false
+	_, err = NewUniswapV2Builder(router, weth, V2Path{usdc, weth}, false).BuildSwap(context.Background(), params)
+	require.Error(t, err)
+}
+
+func TestUniswapV2Quoter(t *testing.T) {
+	var result []byte
+	for _, v := range []int64{32, 3, 1e18, 2500_000000, 2400_000000} {
+		result = append(result, int256Word(v)...)
+	}
+	caller := &fakeContractCaller{result: result}
+	router := common.HexToAddress("0x7a250d5630B4cF539739dF2C5dAcb4c659F2488D")
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	usdt := common.HexToAddress("0xdac17f958d2ee523a2206206994597c13d831ec7")
+
+	quoter := NewUniswapV2Quoter(caller, router, weth, V2Path{weth, usdc, usdt})
+	amountOut, err := quoter.QuoteAmountOut(context.Background(), SwapParams{
+		InputToken:  ethAddress,
+		OutputToken: usdt,
+		AmountIn:    big.NewInt(1e18),
+	})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2400_000000), amountOut)
+	require.Len(t, caller.msgs, 1)
+	require.Equal(t, router, *caller.msgs[0].To)
+	require.Equal(t, "0xd06ca61f", hexutil.Encode(caller.msgs[0].Data[:4]))
+}
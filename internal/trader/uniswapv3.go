@@ -0,0 +1,120 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+//nolint:gochecknoglobals
+var ethAddress = common.HexToAddress("0xeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee")
+
+// UniswapV3Builder builds SwapRouter02 swaps along a route. Swaps to ETH
+// go to the router, which unwraps WETH to the recipient in the same
+// multicall, and unspent ETH of exact output swaps is refunded. Swaps with a
+// deadline are always wrapped in a multicall carrying it.
+type UniswapV3Builder struct {
+	router common.Address
+	weth   common.Address
+	route  Route
+}
+
+func NewUniswapV3Builder(router, weth common.Address, route Route) *UniswapV3Builder {
+	return &UniswapV3Builder{
+		router: router,
+		weth:   weth,
+		route:  route,
+	}
+}
+
+func (b *UniswapV3Builder) BuildSwap(_ context.Context, params SwapParams) (Call, error) {
+	tokens, fees, err := b.route.path(params.InputToken, params.OutputToken, b.weth)
+	if err != nil {
+		return Call{}, err
+	}
+
+	recipient := params.Recipient
+	if isEth(params.OutputToken) {
+		recipient = blockchain.RouterAddressThis
+	}
+
+	var multicall blockchain.Multicall02
+	switch {
+	case params.ExactOutput && b.route.IsMultiHop():
+		err = multicall.ExactOutput(tokens, fees, recipient, params.AmountOut, params.AmountIn)
+	case params.ExactOutput:
+		err = multicall.ExactOutputSingle(tokens[0], tokens[1], recipient, params.AmountOut, params.AmountIn, fees[0])
+	case b.route.IsMultiHop():
+		err = multicall.ExactInput(tokens, fees, recipient, params.AmountIn, params.MinAmountOut)
+	default:
+		err = multicall.ExactInputSingle(tokens[0], tokens[1], recipient, params.AmountIn, params.MinAmountOut, fees[0])
+	}
+	if err != nil {
+		return Call{}, err
+	}
+
+	if isEth(params.OutputToken) {
+		amountMin := params.MinAmountOut
+		if params.ExactOutput {
+			amountMin = params.AmountOut
+		}
+		if err = multicall.UnwrapWETH9(amountMin, params.Recipient); err != nil {
+			return Call{}, err
+		}
+	}
+	if isEth(params.InputToken) && params.ExactOutput {
+		if err = multicall.RefundETH(); err != nil {
+			return Call{}, err
+		}
+	}
+
+	var data []byte
+	if params.Deadline.IsZero() {
+		data, err = multicall.Encode()
+	} else {
+		data, err = multicall.EncodeWithDeadline(params.Deadline.Unix())
+	}
+	if err != nil {
+		return Call{}, err
+	}
+
+	call := Call{To: b.router, Data: data}
+	if isEth(params.InputToken) {
+		call.Value = params.AmountIn
+	}
+
+	return call, nil
+}
+
+// DecodeAmountOut decodes the amount out of an exact input swap, which is the
+// result of the first call when the swap is part of a multicall.
+func (b *UniswapV3Builder) DecodeAmountOut(data []byte) (*big.Int, error) {
+	if len(data) > common.HashLength {
+		results, err := blockchain.DecodeMulticall02(data)
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return nil, errors.New("empty multicall results")
+		}
+		data = results[0]
+	}
+
+	return blockchain.DecodeExactInputSingleOutput(data)
+}
+
+func isEth(token common.Address) bool {
+	return token == ethAddress
+}
+
+func toTokenAddress(token common.Address, weth common.Address) common.Address {
+	if isEth(token) {
+		return weth
+	}
+
+	return token
+}
@@ -0,0 +1,44 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniswapV3BuilderETHOutput(t *testing.T) {
+	cfg := testConfig()
+	router := common.HexToAddress(cfg.RouterAddress)
+	builder := NewUniswapV3Builder(router, common.HexToAddress(cfg.Weth), DirectRoute(big.NewInt(500)))
+
+	call, err := builder.BuildSwap(context.Background(), SwapParams{
+		Recipient:    common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"),
+		InputToken:   common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"),
+		OutputToken:  ethAddress,
+		AmountIn:     big.NewInt(2500_000000),
+		MinAmountOut: big.NewInt(1e18),
+	})
+	require.NoError(t, err)
+	require.Equal(t, router, call.To)
+	require.Nil(t, call.Value)
+	require.Equal(t, "0xac9650d8", hexutil.Encode(call.Data[:4])) // multicall(bytes[])
+
+	// The amount out is the result of the swap, the first call. Results are
+	// the ABI encoded bytes[]{uint256(1e18), {}}.
+	results := append(append(int256Word(32), int256Word(2)...), int256Word(64)...)
+	results = append(results, int256Word(128)...)
+	results = append(results, int256Word(32)...)
+	results = append(results, int256Word(1e18)...)
+	results = append(results, int256Word(0)...)
+	amountOut, err := builder.DecodeAmountOut(results)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1e18), amountOut)
+
+	amountOut, err = builder.DecodeAmountOut(int256Word(2e18))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(2e18), amountOut)
+}
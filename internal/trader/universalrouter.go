@@ -0,0 +1,86 @@
+package trader
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/hiepnv90/ilo/internal/blockchain"
+)
+
+// UniversalRouterBuilder builds Universal Router execute calls swapping
+// along a Uniswap V3 route. ERC20 inputs are pulled through Permit2,
+// submitting the permit in the same call when one is given, while ETH inputs
+// are wrapped and ETH outputs unwrapped by the router.
+type UniversalRouterBuilder struct {
+	router common.Address
+	weth   common.Address
+	route  Route
+}
+
+func NewUniversalRouterBuilder(router, weth common.Address, route Route) *UniversalRouterBuilder {
+	return &UniversalRouterBuilder{
+		router: router,
+		weth:   weth,
+		route:  route,
+	}
+}
+
+func (b *UniversalRouterBuilder) BuildSwap(_ context.Context, params SwapParams) (Call, error) {
+	if params.ExactOutput {
+		return Call{}, errors.New("universal router builder does not support exact output")
+	}
+
+	tokens, fees, err := b.route.path(params.InputToken, params.OutputToken, b.weth)
+	if err != nil {
+		return Call{}, err
+	}
+	path, err := blockchain.EncodePath(tokens, fees)
+	if err != nil {
+		return Call{}, err
+	}
+
+	var commands blockchain.UniversalRouterCommands
+	call := Call{To: b.router}
+
+	payerIsUser := true
+	if isEth(params.InputToken) {
+		if err = commands.WrapETH(blockchain.RouterAddressThis, params.AmountIn); err != nil {
+			return Call{}, err
+		}
+		call.Value = params.AmountIn
+		payerIsUser = false
+	} else if params.Permit != nil {
+		if err = commands.Permit2Permit(*params.Permit, params.PermitSignature); err != nil {
+			return Call{}, err
+		}
+	}
+
+	swapRecipient := params.Recipient
+	if isEth(params.OutputToken) {
+		swapRecipient = blockchain.RouterAddressThis
+	}
+	err = commands.V3SwapExactIn(swapRecipient, params.AmountIn, params.MinAmountOut, path, payerIsUser)
+	if err != nil {
+		return Call{}, err
+	}
+
+	if isEth(params.OutputToken) {
+		if err = commands.UnwrapWETH(params.Recipient, params.MinAmountOut); err != nil {
+			return Call{}, err
+		}
+	}
+
+	deadline := params.Deadline
+	if deadline.IsZero() {
+		deadline = time.Now().Add(defaultSwapDeadline)
+	}
+	call.Data, err = commands.EncodeExecute(deadline.Unix())
+	if err != nil {
+		return Call{}, err
+	}
+
+	return call, nil
+}
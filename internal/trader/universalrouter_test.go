@@ -0,0 +1,34 @@
+package trader
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUniversalRouterBuilderETH(t *testing.T) {
+	router := common.HexToAddress("0x3fC91A3afd70395Cd496C647d5a6CC9D4B2b7FAD")
+	weth := common.HexToAddress("0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2")
+	usdc := common.HexToAddress("0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48")
+	builder := NewUniversalRouterBuilder(router, weth, DirectRoute(big.NewInt(500)))
+
+	params := SwapParams{
+		Recipient:    common.HexToAddress("0x2c7536E3605D9C16a7a3D7b1898e529396a65c23"),
+		InputToken:   ethAddress,
+		OutputToken:  usdc,
+		AmountIn:     big.NewInt(1e18),
+		MinAmountOut: big.NewInt(2500_000000),
+	}
+	call, err := builder.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Equal(t, router, call.To)
+	require.Equal(t, big.NewInt(1e18), call.Value)
+
+	params.InputToken, params.OutputToken = usdc, ethAddress
+	call, err = builder.BuildSwap(context.Background(), params)
+	require.NoError(t, err)
+	require.Nil(t, call.Value)
+}